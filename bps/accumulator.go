@@ -0,0 +1,160 @@
+package bps
+
+import (
+	"encoding"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// make sure that the *Accumulator implements some interfaces.
+var _ interface {
+	fmt.Stringer
+	encoding.TextMarshaler
+	encoding.TextUnmarshaler
+} = (*Accumulator)(nil)
+
+// Accumulator accumulates exact values and emits them in whole units, carrying the remainder over to the next emission.
+// It's for repeated accruals like daily interest, which would lose the truncated remainders every day otherwise:
+//
+//	acc := bps.NewAccumulator(bps.Unity)
+//	for day := 0; day < 365; day++ {
+//		acc.AddProduct(rate, balance, 365)
+//		post(acc.Emit()) // whole yen, and the fraction is kept in acc
+//	}
+//
+// Its state can be persisted by MarshalText and restored by UnmarshalText.
+// It's safe for concurrent use, and it implements the expvar.Var interface by String, so it can be published as it is:
+//
+//	expvar.Publish("interest_remainder", acc)
+//
+// The zero value of Accumulator is empty and emits in Unity. It must not be copied after first use.
+type Accumulator struct {
+	mu   sync.Mutex
+	unit Unit
+	// remainder is the exact accumulated amount which is not emitted yet.
+	remainder *big.Rat
+}
+
+// NewAccumulator returns a new empty Accumulator which emits multiples of `u`.
+func NewAccumulator(u Unit) *Accumulator {
+	return &Accumulator{unit: u}
+}
+
+// Unit returns the unit of the emitted values.
+func (a *Accumulator) Unit() Unit {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.unitLocked()
+}
+
+// Remainder returns the exact amount accumulated but not emitted yet as a new big.Rat instance, e.g. 1/73 means 1/73 amount.
+func (a *Accumulator) Remainder() *big.Rat {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.remainderLocked()
+}
+
+// AddRat adds the exact amount r like 1/3 to `a`.
+func (a *Accumulator) AddRat(r *big.Rat) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.remainder == nil {
+		a.remainder = new(big.Rat)
+	}
+	a.remainder.Add(a.remainder, r)
+}
+
+// Add adds `b` to `a`.
+func (a *Accumulator) Add(b *BPS) {
+	a.AddRat(b.Rat())
+}
+
+// AddProduct adds r * amt / den exactly to `a` without rounding unlike Rate.Mul and BPS.Div,
+// e.g. AddProduct(rate, balance, 365) adds the daily interest of an annual rate.
+// It panics if den is zero.
+func (a *Accumulator) AddProduct(r Rate, amt Amount, den int64) {
+	if den == 0 {
+		panic("division by zero")
+	}
+	v := new(big.Rat).Mul(r.v.Rat(), amt.v.Rat())
+	a.AddRat(v.Quo(v, new(big.Rat).SetInt64(den)))
+}
+
+// Emit returns the whole units of Unit in the accumulated value truncated toward zero, and subtracts them from `a`.
+// The remainder is less than one Unit in magnitude and has the same sign as the accumulated value.
+func (a *Accumulator) Emit() *BPS {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.remainder == nil || a.remainder.Sign() == 0 {
+		return new(BPS)
+	}
+	// count = remainder / (denom / DenomAmount) truncated
+	d := big.NewInt(a.unitLocked().denom())
+	num := new(big.Int).Mul(a.remainder.Num(), big.NewInt(DenomAmount))
+	count := quoRound(num, new(big.Int).Mul(a.remainder.Denom(), d), RoundDown)
+
+	emitted := newBPS(count.Mul(count, d))
+	a.remainder.Sub(a.remainder, emitted.Rat())
+	return emitted
+}
+
+// String returns the state of `a` as a JSON string, which is the text of MarshalText in double quotes.
+// It implements the expvar.Var interface.
+func (a *Accumulator) String() string {
+	text, _ := a.MarshalText()
+	return strconv.Quote(string(text))
+}
+
+// MarshalText implements the encoding.TextMarshaler interface to persist the state of `a` like "3/73 bp",
+// which is the remainder in amount and the unit.
+func (a *Accumulator) MarshalText() ([]byte, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	u := a.unitLocked().String()
+	if a.unitLocked() == Unity {
+		u = "amount"
+	}
+	return []byte(a.remainderLocked().RatString() + " " + u), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface to restore the state marshalled by MarshalText.
+func (a *Accumulator) UnmarshalText(text []byte) error {
+	fields := strings.Fields(string(text))
+	if len(fields) != 2 {
+		return fmt.Errorf("can't convert %s to Accumulator", text)
+	}
+	r, ok := new(big.Rat).SetString(fields[0])
+	if !ok {
+		return fmt.Errorf("can't convert %s to Accumulator: invalid remainder", text)
+	}
+	u := Unity
+	if fields[1] != "amount" {
+		var err error
+		if u, err = ParseUnit(fields[1]); err != nil {
+			return fmt.Errorf("can't convert %s to Accumulator: %w", text, err)
+		}
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.unit, a.remainder = u, r
+	return nil
+}
+
+// unitLocked returns the unit of the emitted values while a.mu is held.
+func (a *Accumulator) unitLocked() Unit {
+	if a.unit == 0 {
+		return Unity
+	}
+	return a.unit
+}
+
+// remainderLocked returns a copy of the remainder while a.mu is held.
+func (a *Accumulator) remainderLocked() *big.Rat {
+	if a.remainder == nil {
+		return new(big.Rat)
+	}
+	return new(big.Rat).Set(a.remainder)
+}
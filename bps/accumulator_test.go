@@ -0,0 +1,188 @@
+package bps_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"go.mercari.io/go-bps/bps"
+)
+
+func TestAccumulator_DailyAccrual(t *testing.T) {
+	t.Parallel()
+
+	rate := bps.RateFromBPS(bps.NewFromPercentage(3))
+	balance := bps.NewAmount(1234567)
+
+	acc := bps.NewAccumulator(bps.Unity)
+	var total, naive int64
+	for day := 0; day < 365; day++ {
+		acc.AddProduct(rate, balance, 365)
+		emitted := acc.Emit()
+		if !emitted.IsMultipleOf(bps.Unity) {
+			t.Fatalf("Emit() = %v, want a whole amount", emitted.FloatString(9))
+		}
+		total += emitted.Amounts()
+		naive += rate.Mul(balance).BPS().Div(365).Amounts()
+
+		// restore the state every day like a batch job
+		text, err := acc.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		acc = new(bps.Accumulator)
+		if err := acc.UnmarshalText(text); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 1234567 * 3% = 37037.01
+	if total != 37037 {
+		t.Errorf("total = %v, want 37037", total)
+	}
+	if want := big.NewRat(1, 100); acc.Remainder().Cmp(want) != 0 {
+		t.Errorf("Remainder() = %v, want %v", acc.Remainder(), want)
+	}
+	if naive >= total {
+		t.Errorf("naive = %v should lose the remainders", naive)
+	}
+}
+
+func TestAccumulator_Emit(t *testing.T) {
+	tests := map[string]struct {
+		unit          bps.Unit
+		add           []*bps.BPS
+		want          *bps.BPS
+		wantRemainder *big.Rat
+	}{
+		"empty": {
+			bps.Unity, nil,
+			bps.NewFromAmount(0), new(big.Rat),
+		},
+		"less than a unit": {
+			bps.BasisPoint, []*bps.BPS{bps.NewFromDeciBasisPoint(9)},
+			bps.NewFromAmount(0), big.NewRat(9, 100000),
+		},
+		"accumulated to units": {
+			bps.BasisPoint, []*bps.BPS{bps.NewFromDeciBasisPoint(9), bps.NewFromDeciBasisPoint(9), bps.NewFromDeciBasisPoint(9)},
+			bps.NewFromBasisPoint(2), big.NewRat(7, 100000),
+		},
+		"negative is truncated toward zero": {
+			bps.Unity, []*bps.BPS{bps.MustFromString("-2.5")},
+			bps.NewFromAmount(-2), big.NewRat(-1, 2),
+		},
+		"mixed signs": {
+			bps.Percentage, []*bps.BPS{bps.NewFromPercentage(5), bps.NewFromBasisPoint(-150)},
+			bps.NewFromPercentage(3), big.NewRat(1, 200),
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			acc := bps.NewAccumulator(tt.unit)
+			for _, b := range tt.add {
+				acc.Add(b)
+			}
+			if got := acc.Emit(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Emit() = %v, want %v", got.FloatString(9), tt.want.FloatString(9))
+			}
+			if got := acc.Remainder(); got.Cmp(tt.wantRemainder) != 0 {
+				t.Errorf("Remainder() = %v, want %v", got, tt.wantRemainder)
+			}
+			if got := acc.Emit(); !got.IsZero() {
+				t.Errorf("second Emit() = %v, want 0", got.FloatString(9))
+			}
+		})
+	}
+}
+
+func TestAccumulator_AddRat(t *testing.T) {
+	t.Parallel()
+
+	var acc bps.Accumulator
+	for i := 0; i < 3; i++ {
+		acc.AddRat(big.NewRat(1, 3))
+	}
+	if got := acc.Emit(); !got.Equal(bps.NewFromAmount(1)) {
+		t.Errorf("Emit() = %v, want 1", got.FloatString(9))
+	}
+	if acc.Unit() != bps.Unity || acc.Remainder().Sign() != 0 {
+		t.Errorf("Accumulator = %v, want empty in amount", &acc)
+	}
+
+	r := acc.Remainder()
+	r.SetInt64(5)
+	if acc.Remainder().Sign() != 0 {
+		t.Error("Remainder() should return a copy")
+	}
+}
+
+func TestAccumulator_AddProduct_Panic(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if recover() == nil {
+			t.Error("AddProduct() should panic for zero denominator")
+		}
+	}()
+	bps.NewAccumulator(bps.Unity).AddProduct(bps.Rate{}, bps.Amount{}, 0)
+}
+
+func TestAccumulator_UnmarshalText(t *testing.T) {
+	tests := map[string]struct {
+		text          string
+		wantUnit      bps.Unit
+		wantRemainder *big.Rat
+		wantErr       bool
+	}{
+		"amount":                                {"3/73 amount", bps.Unity, big.NewRat(3, 73), false},
+		"basis point":                           {"-1/7 bp", bps.BasisPoint, big.NewRat(-1, 7), false},
+		"percentage":                            {"0 %", bps.Percentage, new(big.Rat), false},
+		"If no unit, it should return an error": {"3/73", 0, nil, true},
+		"If invalid remainder, it should return an error": {"x bp", 0, nil, true},
+		"If invalid unit, it should return an error":      {"1/2 bips", 0, nil, true},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var acc bps.Accumulator
+			err := acc.UnmarshalText([]byte(tt.text))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UnmarshalText() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if acc.Unit() != tt.wantUnit || acc.Remainder().Cmp(tt.wantRemainder) != 0 {
+				t.Errorf("UnmarshalText() = %v, want %v %v", &acc, tt.wantRemainder, tt.wantUnit)
+			}
+			if text, _ := acc.MarshalText(); string(text) != tt.text {
+				t.Errorf("MarshalText() = %s, want %s", text, tt.text)
+			}
+		})
+	}
+}
+
+func ExampleAccumulator() {
+	rate := bps.RateFromBPS(bps.NewFromPercentage(3)) // annual rate
+	balance := bps.NewAmount(1000)
+
+	acc := bps.NewAccumulator(bps.Unity)
+	var posted int64
+	for day := 0; day < 365; day++ {
+		acc.AddProduct(rate, balance, 365)
+		posted += acc.Emit().Amounts()
+	}
+	fmt.Println(posted)
+
+	state, _ := json.Marshal(acc)
+	fmt.Println(string(state))
+	// Output:
+	// 30
+	// "0 amount"
+}
@@ -0,0 +1,155 @@
+package bps
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// make sure that Amount implements some interfaces.
+var _ interface {
+	fmt.Stringer
+	driver.Valuer
+	encoding.TextMarshaler
+} = Amount{}
+
+var _ interface {
+	sql.Scanner
+	encoding.TextUnmarshaler
+} = (*Amount)(nil)
+
+// Amount is a money amount like a principal or a fee, which can have a fraction down to ppb like 396.72355.
+// Unlike BPS, it provides only the operations which are meaningful for an amount, so it can't be mixed up with Rate.
+// The zero value of Amount is 0.
+type Amount struct {
+	v BPS
+}
+
+// NewAmount returns a new Amount from an integer amount.
+func NewAmount(amt int64) Amount {
+	var a Amount
+	a.v.SetInt64In(Unity, amt)
+	return a
+}
+
+// AmountFromBPS returns a new Amount from `b` which means an amount, e.g. the result of BPS.Mul.
+func AmountFromBPS(b *BPS) Amount {
+	var a Amount
+	a.v.Set(b)
+	return a
+}
+
+// BPS returns `a` as a new BPS for backward compatibility.
+func (a Amount) BPS() *BPS {
+	return new(BPS).Set(&a.v)
+}
+
+// Add returns a + a2.
+func (a Amount) Add(a2 Amount) Amount {
+	a.v.SetAdd(&a.v, &a2.v)
+	return a
+}
+
+// Sub returns a - a2.
+func (a Amount) Sub(a2 Amount) Amount {
+	a.v.SetSub(&a.v, &a2.v)
+	return a
+}
+
+// Neg returns -a.
+func (a Amount) Neg() Amount {
+	a.v.SetNeg(&a.v)
+	return a
+}
+
+// Div returns the rate of `a` to a2 rounded to ppb by `mode`, e.g. the rate of a fee to a gross amount.
+// It returns an error if a2 is zero.
+func (a Amount) Div(a2 Amount, mode RoundingMode) (Rate, error) {
+	if a2.IsZero() {
+		return Rate{}, fmt.Errorf("Amount.Div: %s / %s: division by zero", a, a2)
+	}
+	var r Rate
+	num := new(big.Int).Mul(a.v.bigValue(), big.NewInt(DenomAmount))
+	r.v.setBig(quoRound(num, a2.v.bigValue(), mode))
+	return r, nil
+}
+
+// Round returns `a` rounded to an integer amount by `mode`.
+func (a Amount) Round(mode RoundingMode) Amount {
+	a.v.Set(a.v.RoundTo(Unity, mode))
+	return a
+}
+
+// Int64 returns `a` rounded to an integer amount by `mode`.
+// It returns ErrOverflow if the amount doesn't fit in int64.
+func (a Amount) Int64(mode RoundingMode) (int64, error) {
+	return a.v.RoundTo(Unity, mode).AmountsInt64()
+}
+
+// Cmp compares `a` and a2 and returns -1, 0 or +1.
+func (a Amount) Cmp(a2 Amount) int {
+	return a.v.Cmp(&a2.v)
+}
+
+// Equal reports whether a == a2.
+func (a Amount) Equal(a2 Amount) bool {
+	return a.v.Equal(&a2.v)
+}
+
+// IsZero reports whether `a` is 0.
+func (a Amount) IsZero() bool {
+	return a.v.IsZero()
+}
+
+// Sign returns -1 if a < 0, 0 if a == 0 and +1 if a > 0.
+func (a Amount) Sign() int {
+	return a.v.Sign()
+}
+
+// String returns the canonical string representation of `a` like "396.72355".
+func (a Amount) String() string {
+	return a.v.CanonicalString()
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// It uses the canonical string representation.
+func (a Amount) MarshalText() ([]byte, error) {
+	return a.v.AppendText(nil)
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (a *Amount) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return errors.New("Amount.UnmarshalText: no data")
+	}
+	return a.v.parse(text)
+}
+
+// Value implements the driver.Valuer interface for database serialization.
+// It uses the canonical string representation like "396.72355", which Scan can parse,
+// so the column must be a decimal type with 9 fractional digits like DECIMAL(20, 9) or a string type.
+// An integer column can hold only integer amounts, e.g. after Round, and it's not compatible with the BaseUnit columns of BPS.
+func (a Amount) Value() (driver.Value, error) {
+	return a.v.CanonicalString(), nil
+}
+
+// Scan implements the sql.Scanner interface for database deserialization.
+// Unlike BPS.Scan, an integer is scanned as an integer amount regardless of BaseUnit.
+func (a *Amount) Scan(value interface{}) error {
+	if a == nil {
+		return errors.New("Amount.Scan: nil receiver")
+	}
+	switch v := value.(type) {
+	case int64:
+		a.v.SetInt64In(Unity, v)
+		return nil
+	case string:
+		return a.UnmarshalText([]byte(v))
+	case []byte:
+		return a.UnmarshalText(v)
+	}
+	return errors.New("Amount.Scan: invalid type, supporting only int64, string or []byte")
+}
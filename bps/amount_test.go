@@ -0,0 +1,242 @@
+package bps_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+
+	"go.mercari.io/go-bps/bps"
+)
+
+func TestAmount_Div(t *testing.T) {
+	tests := map[string]struct {
+		a       bps.Amount
+		a2      bps.Amount
+		mode    bps.RoundingMode
+		want    bps.Rate
+		wantErr bool
+	}{
+		"1199.92 / 14999 = 8%": {
+			bps.AmountFromBPS(bps.MustFromString("1199.92")),
+			bps.NewAmount(14999),
+			bps.RoundDown,
+			bps.RateFromBPS(bps.NewFromPercentage(8)),
+			false,
+		},
+		"396 / 14999 rounded up": {
+			bps.NewAmount(396),
+			bps.NewAmount(14999),
+			bps.RoundUp,
+			bps.RateFromBPS(bps.MustFromString("0.026401761")),
+			false,
+		},
+		"-1 / 3 rounded to floor": {
+			bps.NewAmount(-1),
+			bps.NewAmount(3),
+			bps.RoundFloor,
+			bps.RateFromBPS(bps.MustFromString("-0.333333334")),
+			false,
+		},
+		"If divided by zero, it should return an error": {
+			bps.NewAmount(1),
+			bps.Amount{},
+			bps.RoundDown,
+			bps.Rate{},
+			true,
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tt.a.Div(tt.a2, tt.mode)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Amount.Div() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Amount.Div() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAmount_Int64(t *testing.T) {
+	tests := map[string]struct {
+		a       bps.Amount
+		mode    bps.RoundingMode
+		want    int64
+		wantErr bool
+	}{
+		"396.72355 rounded half up": {
+			bps.AmountFromBPS(bps.MustFromString("396.72355")),
+			bps.RoundHalfUp,
+			397,
+			false,
+		},
+		"396.72355 rounded down": {
+			bps.AmountFromBPS(bps.MustFromString("396.72355")),
+			bps.RoundDown,
+			396,
+			false,
+		},
+		"-396.72355 rounded down": {
+			bps.AmountFromBPS(bps.MustFromString("-396.72355")),
+			bps.RoundDown,
+			-396,
+			false,
+		},
+		"max int64": {
+			bps.NewAmount(math.MaxInt64),
+			bps.RoundDown,
+			math.MaxInt64,
+			false,
+		},
+		"If overflows int64, it should return an error": {
+			bps.NewAmount(math.MaxInt64).Add(bps.NewAmount(1)),
+			bps.RoundDown,
+			0,
+			true,
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tt.a.Int64(tt.mode)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Amount.Int64() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && !errors.Is(err, bps.ErrOverflow) {
+				t.Errorf("Amount.Int64() error = %v, want %v", err, bps.ErrOverflow)
+			}
+			if got != tt.want {
+				t.Errorf("Amount.Int64() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAmount_Arithmetic(t *testing.T) {
+	t.Parallel()
+
+	a := bps.NewAmount(1199)
+	a2 := bps.AmountFromBPS(bps.MustFromString("396.72355"))
+
+	if got, want := a.Add(a2), bps.AmountFromBPS(bps.MustFromString("1595.72355")); !got.Equal(want) {
+		t.Errorf("Amount.Add() = %v, want %v", got, want)
+	}
+	if got, want := a.Sub(a2), bps.AmountFromBPS(bps.MustFromString("802.27645")); !got.Equal(want) {
+		t.Errorf("Amount.Sub() = %v, want %v", got, want)
+	}
+	if got, want := a2.Neg().String(), "-396.72355"; got != want {
+		t.Errorf("Amount.Neg() = %v, want %v", got, want)
+	}
+	if got, want := a2.Round(bps.RoundHalfEven), bps.NewAmount(397); !got.Equal(want) {
+		t.Errorf("Amount.Round() = %v, want %v", got, want)
+	}
+	if got := a.Cmp(a2); got != 1 {
+		t.Errorf("Amount.Cmp() = %v, want 1", got)
+	}
+	if !(bps.Amount{}).IsZero() || (bps.Amount{}).Sign() != 0 {
+		t.Error("the zero value of Amount should be zero")
+	}
+	if got, want := a2.BPS(), bps.NewFromDeciBasisPoint(2645).Mul(14999); !got.Equal(want) {
+		t.Errorf("Amount.BPS() = %v, want %v", got, want)
+	}
+}
+
+func TestAmount_DatabaseColumns(t *testing.T) {
+	t.Parallel()
+
+	// an integer column can hold an integer amount, and Scan reads it as an amount
+	principal := bps.NewAmount(14999)
+	v, err := principal.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := strconv.ParseInt(v.(string), 10, 64)
+	if err != nil {
+		t.Fatalf("Amount.Value() = %v, not an integer: %v", v, err)
+	}
+	var got bps.Amount
+	if err := got.Scan(n); err != nil || !got.Equal(principal) {
+		t.Errorf("Amount.Scan(%d) = %v, %v, want %v", n, got, err, principal)
+	}
+
+	// a fraction needs a decimal column
+	fee := bps.AmountFromBPS(bps.MustFromString("396.72355"))
+	v, _ = fee.Value()
+	if _, err := strconv.ParseInt(v.(string), 10, 64); err == nil {
+		t.Errorf("Amount.Value() = %v, want a decimal", v)
+	}
+	if err := got.Scan([]byte("396.723550000")); err != nil || !got.Equal(fee) {
+		t.Errorf("Amount.Scan() = %v, %v, want %v", got, err, fee)
+	}
+}
+
+func TestAmount_Serialization(t *testing.T) {
+	t.Parallel()
+
+	type Bill struct {
+		Fee bps.Amount `json:"fee"`
+	}
+	bill := Bill{Fee: bps.AmountFromBPS(bps.MustFromString("396.72355"))}
+	data, err := json.Marshal(bill)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"fee":"396.72355"}`; string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+	var got Bill
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !got.Fee.Equal(bill.Fee) {
+		t.Errorf("json.Unmarshal() = %v, want %v", got.Fee, bill.Fee)
+	}
+
+	tests := map[string]struct {
+		value   interface{}
+		want    bps.Amount
+		wantErr bool
+	}{
+		"Value() output": {
+			func() interface{} { v, _ := bill.Fee.Value(); return v }(),
+			bill.Fee,
+			false,
+		},
+		"int64 is an integer amount regardless of BaseUnit": {
+			int64(15),
+			bps.NewAmount(15),
+			false,
+		},
+		"[]byte": {
+			[]byte("-1.5"),
+			bps.AmountFromBPS(bps.MustFromString("-1.5")),
+			false,
+		},
+		"If invalid type, it should return an error": {
+			1.5,
+			bps.Amount{},
+			true,
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var a bps.Amount
+			if err := a.Scan(tt.value); (err != nil) != tt.wantErr {
+				t.Errorf("Amount.Scan() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !a.Equal(tt.want) {
+				t.Errorf("Amount.Scan() = %v, want %v", a, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package bps
+
+import (
+	"math/big"
+	"sync"
+	"sync/atomic"
+)
+
+// AtomicBPS is a BPS which is safe for concurrent use, e.g. to sum fees from many goroutines.
+// The zero value of AtomicBPS is 0, and it must not be copied after first use.
+//
+// While the value fits in int64 ppbs, Add and Load are lock-free and don't allocate.
+// Beyond that, the overflowed part is kept in a *big.Int guarded by a mutex.
+type AtomicBPS struct {
+	// ppb is accessed atomically, and it's the first field to be 64-bit aligned on 32-bit platforms.
+	ppb int64
+	// seq is a sequence number accessed atomically. It's odd while the value is being changed other than by adding to ppb,
+	// so Load can read ppb without the lock if seq is even and not changed during the read.
+	seq uint32
+	// overflowed is 1 if extra is not nil. It's accessed atomically.
+	overflowed uint32
+
+	mu sync.Mutex
+	// extra is the part of the value which doesn't fit in ppb, that means the value is ppb + extra.
+	// It's never mutated, since it may be shared with a BPS.
+	extra *big.Int
+}
+
+// Load returns the current value.
+func (a *AtomicBPS) Load() *BPS {
+	s := atomic.LoadUint32(&a.seq)
+	if s%2 == 0 && atomic.LoadUint32(&a.overflowed) == 0 {
+		v := atomic.LoadInt64(&a.ppb)
+		if atomic.LoadUint32(&a.seq) == s {
+			return &BPS{ppb: v}
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return newBPS(a.sumLocked(atomic.LoadInt64(&a.ppb)))
+}
+
+// Add adds delta to the value.
+func (a *AtomicBPS) Add(delta *BPS) {
+	d := nilSafe(delta)
+	if d.value == nil {
+		for {
+			old := atomic.LoadInt64(&a.ppb)
+			v, ok := add64(&BPS{ppb: old}, d)
+			if !ok {
+				break
+			}
+			if atomic.CompareAndSwapInt64(&a.ppb, old, v) {
+				return
+			}
+		}
+	}
+
+	// ppb would overflow, so add delta to extra instead
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.beginLocked()
+	defer a.endLocked()
+	a.extra = a.sumLocked(0)
+	a.extra.Add(a.extra, d.bigValue())
+	if a.extra.Sign() == 0 {
+		a.extra = nil
+	}
+}
+
+// Store sets the value to `b`.
+func (a *AtomicBPS) Store(b *BPS) {
+	a.Swap(b)
+}
+
+// Swap sets the value to `b` and returns the old value.
+func (a *AtomicBPS) Swap(b *BPS) *BPS {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.beginLocked()
+	defer a.endLocked()
+
+	v, extra := split(b)
+	old := a.sumLocked(atomic.SwapInt64(&a.ppb, v))
+	a.extra = extra
+	return newBPS(old)
+}
+
+// CompareAndSwap sets the value to new and returns true if the value is old, otherwise it returns false.
+func (a *AtomicBPS) CompareAndSwap(old, new *BPS) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.beginLocked()
+	defer a.endLocked()
+
+	v, extra := split(new)
+	for {
+		// ppb may be changed by Add concurrently, but extra is not
+		cur := atomic.LoadInt64(&a.ppb)
+		if newBPS(a.sumLocked(cur)).Cmp(old) != 0 {
+			return false
+		}
+		if atomic.CompareAndSwapInt64(&a.ppb, cur, v) {
+			a.extra = extra
+			return true
+		}
+	}
+}
+
+// Reset sets the value to 0 and returns the old value.
+func (a *AtomicBPS) Reset() *BPS {
+	return a.Swap(nil)
+}
+
+// sumLocked returns v + extra as a new big.Int instance while a.mu is held.
+func (a *AtomicBPS) sumLocked(v int64) *big.Int {
+	res := big.NewInt(v)
+	if a.extra != nil {
+		res.Add(res, a.extra)
+	}
+	return res
+}
+
+// beginLocked makes seq odd before changing the value while a.mu is held.
+func (a *AtomicBPS) beginLocked() {
+	atomic.AddUint32(&a.seq, 1)
+}
+
+// endLocked updates overflowed and makes seq even after changing the value while a.mu is held.
+func (a *AtomicBPS) endLocked() {
+	if a.extra == nil {
+		atomic.StoreUint32(&a.overflowed, 0)
+	} else {
+		atomic.StoreUint32(&a.overflowed, 1)
+	}
+	atomic.AddUint32(&a.seq, 1)
+}
+
+// split returns `b` as ppb and extra of AtomicBPS.
+func split(b *BPS) (int64, *big.Int) {
+	b = nilSafe(b)
+	if b.value == nil {
+		return b.ppb, nil
+	}
+	return 0, b.value
+}
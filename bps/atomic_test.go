@@ -0,0 +1,211 @@
+package bps_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sync"
+	"testing"
+
+	"go.mercari.io/go-bps/bps"
+)
+
+func TestAtomicBPS_ConcurrentAdd(t *testing.T) {
+	tests := map[string]struct {
+		initial *bps.BPS
+		delta   *bps.BPS
+	}{
+		"int64": {
+			nil,
+			bps.NewFromDeciBasisPoint(2645),
+		},
+		"overflow int64": {
+			bps.NewFromPPB(big.NewInt(math.MaxInt64 - 1000)),
+			bps.NewFromPPB(big.NewInt(7)),
+		},
+		"negative overflow": {
+			bps.NewFromPPB(big.NewInt(math.MinInt64 + 1000)),
+			bps.NewFromPPB(big.NewInt(-7)),
+		},
+		"big delta": {
+			bps.NewFromAmount(1),
+			bps.NewFromPPB(new(big.Int).Lsh(big.NewInt(1), 70)),
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			const goroutines, adds = 8, 1000
+
+			var a bps.AtomicBPS
+			a.Store(tt.initial)
+			var wg sync.WaitGroup
+			for i := 0; i < goroutines; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for j := 0; j < adds; j++ {
+						a.Add(tt.delta)
+						_ = a.Load()
+					}
+				}()
+			}
+			wg.Wait()
+
+			want := tt.delta.Mul(goroutines * adds).Add(tt.initial)
+			if got := a.Load(); !got.Equal(want) {
+				t.Errorf("AtomicBPS.Load() = %v, want %v", got.PPBs(), want.PPBs())
+			}
+		})
+	}
+}
+
+func TestAtomicBPS_ConcurrentSwap(t *testing.T) {
+	t.Parallel()
+
+	// every Add is counted exactly once by either a Swap or the last Load
+	const goroutines, adds = 4, 2000
+	var a bps.AtomicBPS
+	a.Store(bps.NewFromPPB(big.NewInt(math.MaxInt64 - 5000)))
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		swapped = new(bps.BPS)
+	)
+	for i := 0; i < goroutines; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < adds; j++ {
+				a.Add(bps.NewFromPPB(big.NewInt(3)))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < adds/100; j++ {
+				old := a.Swap(bps.NewFromPPB(big.NewInt(math.MaxInt64)))
+				mu.Lock()
+				swapped.SetAdd(swapped, old.Sub(bps.NewFromPPB(big.NewInt(math.MaxInt64))))
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	total := a.Load().Add(swapped)
+	want := bps.NewFromPPB(big.NewInt(math.MaxInt64 - 5000)).Add(bps.NewFromPPB(big.NewInt(3 * goroutines * adds)))
+	if !total.Equal(want) {
+		t.Errorf("total = %v, want %v", total.PPBs(), want.PPBs())
+	}
+}
+
+func TestAtomicBPS_CompareAndSwap(t *testing.T) {
+	huge := bps.NewFromPPB(new(big.Int).Lsh(big.NewInt(1), 80))
+	tests := map[string]struct {
+		initial  *bps.BPS
+		old, new *bps.BPS
+		want     bool
+	}{
+		"int64 matched":     {bps.NewFromPercentage(1), bps.NewFromPercentage(1), bps.NewFromPercentage(2), true},
+		"int64 not matched": {bps.NewFromPercentage(1), bps.NewFromPercentage(3), bps.NewFromPercentage(2), false},
+		"zero value":        {nil, nil, bps.NewFromPercentage(2), true},
+		"to big":            {bps.NewFromPercentage(1), bps.NewFromPercentage(1), huge, true},
+		"from big":          {huge, huge, bps.NewFromPercentage(2), true},
+		"big not matched":   {huge, huge.Add(bps.NewFromPPB(big.NewInt(1))), bps.NewFromPercentage(2), false},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var a bps.AtomicBPS
+			a.Store(tt.initial)
+			if got := a.CompareAndSwap(tt.old, tt.new); got != tt.want {
+				t.Errorf("AtomicBPS.CompareAndSwap() = %v, want %v", got, tt.want)
+			}
+			want := tt.initial
+			if tt.want {
+				want = tt.new
+			}
+			if got := a.Load(); !got.Equal(want) {
+				t.Errorf("AtomicBPS.Load() = %v, want %v", got.PPBs(), want.PPBs())
+			}
+		})
+	}
+}
+
+func TestAtomicBPS_SwapAndReset(t *testing.T) {
+	t.Parallel()
+
+	var a bps.AtomicBPS
+	a.Add(bps.NewFromPPB(big.NewInt(math.MaxInt64)))
+	a.Add(bps.NewFromPPB(big.NewInt(math.MaxInt64)))
+	want := bps.NewFromPPB(new(big.Int).Mul(big.NewInt(math.MaxInt64), big.NewInt(2)))
+	if got := a.Swap(bps.NewFromPercentage(1)); !got.Equal(want) {
+		t.Errorf("AtomicBPS.Swap() = %v, want %v", got.PPBs(), want.PPBs())
+	}
+	if got := a.Reset(); !got.Equal(bps.NewFromPercentage(1)) {
+		t.Errorf("AtomicBPS.Reset() = %v, want 1%%", got.PPBs())
+	}
+	if got := a.Load(); !got.IsZero() {
+		t.Errorf("AtomicBPS.Load() = %v, want 0", got.PPBs())
+	}
+
+	// the overflowed part goes back to zero
+	a.Add(bps.NewFromPPB(big.NewInt(math.MaxInt64)))
+	a.Add(bps.NewFromPPB(big.NewInt(1)))
+	a.Add(bps.NewFromPPB(big.NewInt(-1)))
+	if got := a.Load(); !got.Equal(bps.NewFromPPB(big.NewInt(math.MaxInt64))) {
+		t.Errorf("AtomicBPS.Load() = %v, want MaxInt64", got.PPBs())
+	}
+}
+
+func TestAtomicBPS_AllocsPerRun(t *testing.T) {
+	var a bps.AtomicBPS
+	delta := bps.NewFromDeciBasisPoint(2645)
+	if n := testing.AllocsPerRun(100, func() { a.Add(delta) }); n != 0 {
+		t.Errorf("AtomicBPS.Add() allocates %v times", n)
+	}
+}
+
+func BenchmarkAtomicBPS_Add(b *testing.B) {
+	var a bps.AtomicBPS
+	delta := bps.NewFromDeciBasisPoint(2645)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			a.Add(delta)
+		}
+	})
+}
+
+func BenchmarkMutex_SetAdd(b *testing.B) {
+	var (
+		mu    sync.Mutex
+		total = new(bps.BPS)
+	)
+	delta := bps.NewFromDeciBasisPoint(2645)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			mu.Lock()
+			total.SetAdd(total, delta)
+			mu.Unlock()
+		}
+	})
+}
+
+func ExampleAtomicBPS() {
+	var total bps.AtomicBPS
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			total.Add(bps.NewFromDeciBasisPoint(2645))
+		}()
+	}
+	wg.Wait()
+	fmt.Println(total.Load().UnitString(bps.Percentage))
+	// Output:
+	// 26.45%
+}
@@ -0,0 +1,207 @@
+package bps
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+)
+
+// DefaultChunkSize is the number of elements processed by one goroutine at least when Batch.ChunkSize is zero.
+const DefaultChunkSize = 4096
+
+// Batch runs the operations over large slices, e.g. the fees of tens of millions of rows in a settlement.
+// It splits a slice into contiguous chunks and processes them by up to Workers goroutines.
+// The results don't depend on the number of goroutines, since all operations are exact or rounded element by element.
+//
+//	b := bps.Batch{Workers: runtime.GOMAXPROCS(0)}
+//	fees, err := b.ApplyRates(fees[:0], rates, amounts, bps.RoundFloor)
+//
+// The zero value of Batch runs sequentially in the calling goroutine, as the package level functions do.
+type Batch struct {
+	// Workers is the maximum number of goroutines. Zero or one means sequential.
+	Workers int
+	// ChunkSize is the minimum number of elements per goroutine to avoid spawning goroutines for small slices.
+	// Zero means DefaultChunkSize.
+	ChunkSize int
+}
+
+// MulEach returns the amounts of `amounts` at the rate `r` like Batch.MulEach sequentially.
+func MulEach(dst []Amount, r Rate, amounts []Amount) []Amount {
+	return Batch{}.MulEach(dst, r, amounts)
+}
+
+// ApplyRates returns the amounts of `amounts` at `rates` rounded to integer amounts like Batch.ApplyRates sequentially.
+func ApplyRates(dst []Amount, rates []Rate, amounts []Amount, mode RoundingMode) ([]Amount, error) {
+	return Batch{}.ApplyRates(dst, rates, amounts, mode)
+}
+
+// SumSlice returns the total of `amounts` like Batch.SumSlice sequentially.
+func SumSlice(amounts []Amount) Amount {
+	return Batch{}.SumSlice(amounts)
+}
+
+// MulEach sets dst[i] to r.Mul(amounts[i]) and returns dst resliced to len(amounts).
+// dst is reused if it has enough capacity, otherwise a new slice is allocated like append,
+// and it may be `amounts` itself to compute in place.
+func (bt Batch) MulEach(dst []Amount, r Rate, amounts []Amount) []Amount {
+	dst = resize(dst, len(amounts))
+	if !bt.concurrent(len(amounts)) {
+		mulEach(dst, r, amounts)
+		return dst
+	}
+	bt.run(len(amounts), func(lo, hi int) {
+		mulEach(dst[lo:hi], r, amounts[lo:hi])
+	})
+	return dst
+}
+
+// ApplyRates sets dst[i] to rates[i] * amounts[i] rounded to an integer amount by `mode`, and returns dst resliced to len(amounts).
+// Unlike Rate.Mul followed by Amount.Round, the product is rounded only once from the exact value.
+// dst is reused like MulEach. It returns an error if the lengths of rates and amounts differ.
+func (bt Batch) ApplyRates(dst []Amount, rates []Rate, amounts []Amount, mode RoundingMode) ([]Amount, error) {
+	if len(rates) != len(amounts) {
+		return dst, fmt.Errorf("ApplyRates: the lengths of rates and amounts differ: %d != %d", len(rates), len(amounts))
+	}
+	dst = resize(dst, len(amounts))
+	if !bt.concurrent(len(amounts)) {
+		applyRates(dst, rates, amounts, mode)
+		return dst, nil
+	}
+	bt.run(len(amounts), func(lo, hi int) {
+		applyRates(dst[lo:hi], rates[lo:hi], amounts[lo:hi], mode)
+	})
+	return dst, nil
+}
+
+// SumSlice returns the total of `amounts`.
+// It keeps the total in int64 and spills to *big.Int only if the total overflows, so it doesn't allocate in the common case.
+func (bt Batch) SumSlice(amounts []Amount) Amount {
+	var total Amount
+	if !bt.concurrent(len(amounts)) {
+		total.v.setSum(amounts)
+		return total
+	}
+
+	size := bt.chunkSize(len(amounts))
+	totals := make([]BPS, (len(amounts)+size-1)/size)
+	bt.run(len(amounts), func(lo, hi int) {
+		totals[lo/size].setSum(amounts[lo:hi])
+	})
+	// the totals are exact, so the result doesn't depend on how the slice is split.
+	for i := range totals {
+		total.v.SetAdd(&total.v, &totals[i])
+	}
+	return total
+}
+
+// concurrent reports whether n elements are split into two or more chunks.
+// The callers process them in the calling goroutine otherwise, not to allocate the closure for run.
+func (bt Batch) concurrent(n int) bool {
+	return bt.chunkSize(n) < n
+}
+
+// run calls f with the bounds [lo, hi) of each chunk of n elements concurrently.
+func (bt Batch) run(n int, f func(lo, hi int)) {
+	size := bt.chunkSize(n)
+	var wg sync.WaitGroup
+	for lo := 0; lo < n; lo += size {
+		hi := lo + size
+		if hi > n {
+			hi = n
+		}
+		wg.Add(1)
+		go func(lo, hi int) {
+			defer wg.Done()
+			f(lo, hi)
+		}(lo, hi)
+	}
+	wg.Wait()
+}
+
+// chunkSize returns the number of elements in each chunk to split n elements into.
+// The last chunk may be smaller than that.
+func (bt Batch) chunkSize(n int) int {
+	if bt.Workers <= 1 {
+		return n
+	}
+	min := bt.ChunkSize
+	if min <= 0 {
+		min = DefaultChunkSize
+	}
+	size := (n + bt.Workers - 1) / bt.Workers
+	if size < min {
+		return min
+	}
+	return size
+}
+
+// resize returns a slice of length n which reuses dst if it has enough capacity.
+func resize(dst []Amount, n int) []Amount {
+	if cap(dst) < n {
+		return make([]Amount, n)
+	}
+	return dst[:n]
+}
+
+// mulEach sets dst[i] to r.Mul(amounts[i]).
+func mulEach(dst []Amount, r Rate, amounts []Amount) {
+	for i := range amounts {
+		dst[i] = r.Mul(amounts[i])
+	}
+}
+
+// applyRates sets dst[i] to rates[i] * amounts[i] rounded to an integer amount by `mode`.
+func applyRates(dst []Amount, rates []Rate, amounts []Amount, mode RoundingMode) {
+	for i := range amounts {
+		dst[i].v.setProduct(&rates[i].v, &amounts[i].v, mode)
+	}
+}
+
+// setSum sets `b` to the total of `amounts` and returns `b`.
+func (b *BPS) setSum(amounts []Amount) *BPS {
+	var (
+		sum   int64
+		spill *big.Int
+	)
+	for i := range amounts {
+		v := &amounts[i].v
+		if v.value == nil {
+			s := sum + v.ppb
+			if (sum^s)&(v.ppb^s) >= 0 {
+				sum = s
+				continue
+			}
+		}
+		// move the int64 total to spill on overflow.
+		if spill == nil {
+			spill = new(big.Int)
+		}
+		spill.Add(spill, big.NewInt(sum))
+		sum = 0
+		if v.value != nil {
+			spill.Add(spill, v.value)
+		} else {
+			sum = v.ppb
+		}
+	}
+	if spill == nil {
+		return b.setInt64(sum)
+	}
+	return b.setBig(spill.Add(spill, big.NewInt(sum)))
+}
+
+// setProduct sets `b` to r * a rounded to an integer amount by `mode`, and returns `b`.
+func (b *BPS) setProduct(r, a *BPS, mode RoundingMode) *BPS {
+	// the product of a rate and an integer amount is exact in ppb, so it can be rounded in int64.
+	if a.value == nil && a.ppb%DenomAmount == 0 {
+		if p, ok := mul64(r, a.ppb/DenomAmount); ok {
+			if v, ok := mul64(&BPS{ppb: quoRound64(p, DenomAmount, mode)}, DenomAmount); ok {
+				return b.setInt64(v)
+			}
+		}
+	}
+	d := big.NewInt(DenomAmount)
+	p := new(big.Int).Mul(r.bigValue(), a.bigValue())
+	q := quoRound(p, new(big.Int).Mul(d, d), mode)
+	return b.setBig(q.Mul(q, d))
+}
@@ -0,0 +1,282 @@
+package bps_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"go.mercari.io/go-bps/bps"
+)
+
+func TestApplyRates(t *testing.T) {
+	rate := func(s string) bps.Rate { return bps.RateFromBPS(bps.MustFromString(s)) }
+	amount := func(s string) bps.Amount { return bps.AmountFromBPS(bps.MustFromString(s)) }
+
+	tests := map[string]struct {
+		r    bps.Rate
+		a    bps.Amount
+		mode bps.RoundingMode
+		want bps.Amount
+	}{
+		"2.645% of 14999 is floored to 396": {
+			rate("0.02645"), bps.NewAmount(14999), bps.RoundFloor, bps.NewAmount(396),
+		},
+		"2.645% of 14999 is rounded half up to 397": {
+			rate("0.02645"), bps.NewAmount(14999), bps.RoundHalfUp, bps.NewAmount(397),
+		},
+		"50% of 5 is rounded half even to 2": {
+			rate("0.5"), bps.NewAmount(5), bps.RoundHalfEven, bps.NewAmount(2),
+		},
+		"50% of 7 is rounded half even to 4": {
+			rate("0.5"), bps.NewAmount(7), bps.RoundHalfEven, bps.NewAmount(4),
+		},
+		"50% of -5 is rounded half down to -2": {
+			rate("0.5"), bps.NewAmount(-5), bps.RoundHalfDown, bps.NewAmount(-2),
+		},
+		"-2.645% of 14999 is floored to -397": {
+			rate("-0.02645"), bps.NewAmount(14999), bps.RoundFloor, bps.NewAmount(-397),
+		},
+		"1 ppb of 0.5 is rounded up from the exact value": {
+			rate("0.000000001"), amount("0.5"), bps.RoundCeiling, bps.NewAmount(1),
+		},
+		"50% of 1.000000001 is rounded half down from the exact value": {
+			rate("0.5"), amount("1.000000001"), bps.RoundHalfDown, bps.NewAmount(1),
+		},
+		"the product overflowing int64 is rounded by big.Int": {
+			rate("100"), bps.NewAmount(math.MaxInt64 / 1000), bps.RoundFloor, amount("922337203685477500"),
+		},
+		"the result overflowing int64 is kept": {
+			rate("3"), bps.NewAmount(math.MaxInt64 / bps.DenomAmount), bps.RoundDown, amount("27670116108"),
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := bps.ApplyRates(nil, []bps.Rate{tt.r}, []bps.Amount{tt.a}, tt.mode)
+			if err != nil {
+				t.Fatalf("ApplyRates() error = %v", err)
+			}
+			if len(got) != 1 || !got[0].Equal(tt.want) {
+				t.Errorf("ApplyRates() = %v, want [%v]", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyRates_RoundOnce(t *testing.T) {
+	t.Parallel()
+
+	// the product of an integer amount is exact, so ApplyRates must be the same as Rate.Mul followed by Amount.Round.
+	r := rand.New(rand.NewSource(1))
+	rates := make([]bps.Rate, 1000)
+	amounts := make([]bps.Amount, len(rates))
+	for i := range rates {
+		rates[i] = bps.RateFromBPS(bps.NewFromPPB(big.NewInt(r.Int63n(2e9) - 1e9)))
+		amounts[i] = bps.NewAmount(r.Int63n(1e12) - 5e11)
+	}
+	rates[0], amounts[0] = bps.RateFromBPS(bps.NewFromPPB(big.NewInt(math.MaxInt64))), bps.NewAmount(math.MaxInt64)
+	rates[1], amounts[1] = bps.RateFromBPS(bps.NewFromPPB(big.NewInt(math.MinInt64))), bps.NewAmount(3)
+
+	modes := []bps.RoundingMode{bps.RoundDown, bps.RoundUp, bps.RoundFloor, bps.RoundCeiling, bps.RoundHalfUp, bps.RoundHalfDown, bps.RoundHalfEven}
+	for _, mode := range modes {
+		got, err := bps.ApplyRates(nil, rates, amounts, mode)
+		if err != nil {
+			t.Fatalf("ApplyRates() error = %v", err)
+		}
+		for i := range got {
+			if want := rates[i].Mul(amounts[i]).Round(mode); !got[i].Equal(want) {
+				t.Errorf("ApplyRates(%v, %v, %v) = %v, want %v", rates[i], amounts[i], mode, got[i], want)
+			}
+		}
+	}
+}
+
+func TestApplyRates_LengthMismatch(t *testing.T) {
+	t.Parallel()
+
+	if _, err := bps.ApplyRates(nil, make([]bps.Rate, 2), make([]bps.Amount, 3), bps.RoundDown); err == nil {
+		t.Error("ApplyRates() error = nil, want an error")
+	}
+}
+
+func TestMulEach(t *testing.T) {
+	t.Parallel()
+
+	r := bps.RateFromBPS(bps.NewFromDeciBasisPoint(2645))
+	amounts := []bps.Amount{bps.NewAmount(14999), bps.AmountFromBPS(bps.MustFromString("0.5")), {}, bps.NewAmount(-3)}
+	want := make([]bps.Amount, len(amounts))
+	for i, a := range amounts {
+		want[i] = r.Mul(a)
+	}
+
+	dst := make([]bps.Amount, 0, 8)
+	got := bps.MulEach(dst, r, amounts)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MulEach() = %v, want %v", got, want)
+	}
+	if &got[0] != &dst[:1][0] {
+		t.Error("MulEach() doesn't reuse dst")
+	}
+
+	// in place
+	if got := bps.MulEach(amounts, r, amounts); !reflect.DeepEqual(got, want) {
+		t.Errorf("MulEach() in place = %v, want %v", got, want)
+	}
+}
+
+func TestSumSlice(t *testing.T) {
+	huge := bps.AmountFromBPS(bps.NewFromPPB(new(big.Int).Lsh(big.NewInt(1), 70)))
+	maxInt64 := bps.AmountFromBPS(bps.NewFromPPB(big.NewInt(math.MaxInt64)))
+
+	tests := map[string]struct {
+		amounts []bps.Amount
+		want    bps.Amount
+	}{
+		"empty": {
+			nil,
+			bps.Amount{},
+		},
+		"int64": {
+			[]bps.Amount{bps.NewAmount(1), bps.NewAmount(2), bps.NewAmount(-4)},
+			bps.NewAmount(-1),
+		},
+		"overflow int64 and come back": {
+			[]bps.Amount{maxInt64, maxInt64, maxInt64.Neg(), bps.NewAmount(1), maxInt64.Neg()},
+			bps.NewAmount(1),
+		},
+		"big": {
+			[]bps.Amount{huge, bps.NewAmount(1), huge},
+			huge.Add(huge).Add(bps.NewAmount(1)),
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if got := bps.SumSlice(tt.amounts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SumSlice() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBatch_Deterministic(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1))
+	rates := make([]bps.Rate, 10007)
+	amounts := make([]bps.Amount, len(rates))
+	for i := range rates {
+		rates[i] = bps.RateFromBPS(bps.NewFromPPB(big.NewInt(r.Int63n(1e8))))
+		amounts[i] = bps.AmountFromBPS(bps.NewFromPPB(big.NewInt(r.Int63() - r.Int63())))
+	}
+
+	want, err := bps.ApplyRates(nil, rates, amounts, bps.RoundHalfEven)
+	if err != nil {
+		t.Fatalf("ApplyRates() error = %v", err)
+	}
+	wantMul := bps.MulEach(nil, rates[0], amounts)
+	wantSum := bps.SumSlice(amounts)
+
+	for _, b := range []bps.Batch{{Workers: 2}, {Workers: 3, ChunkSize: 1}, {Workers: 8, ChunkSize: 100}, {Workers: 100000, ChunkSize: 1}} {
+		got, err := b.ApplyRates(nil, rates, amounts, bps.RoundHalfEven)
+		if err != nil {
+			t.Fatalf("Batch%+v.ApplyRates() error = %v", b, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Batch%+v.ApplyRates() differs from the sequential one", b)
+		}
+		if got := b.MulEach(nil, rates[0], amounts); !reflect.DeepEqual(got, wantMul) {
+			t.Errorf("Batch%+v.MulEach() differs from the sequential one", b)
+		}
+		if got := b.SumSlice(amounts); !reflect.DeepEqual(got, wantSum) {
+			t.Errorf("Batch%+v.SumSlice() = %v, want %v", b, got, wantSum)
+		}
+	}
+}
+
+func TestBatch_AllocsPerRun(t *testing.T) {
+	rates := []bps.Rate{bps.RateFromBPS(bps.NewFromDeciBasisPoint(2645)), bps.RateFromBPS(bps.NewFromPercentage(8))}
+	amounts := []bps.Amount{bps.NewAmount(14999), bps.NewAmount(3000)}
+	dst := make([]bps.Amount, len(amounts))
+
+	if n := testing.AllocsPerRun(100, func() { dst, _ = bps.ApplyRates(dst, rates, amounts, bps.RoundFloor) }); n != 0 {
+		t.Errorf("ApplyRates() allocates %v times", n)
+	}
+	if n := testing.AllocsPerRun(100, func() { dst = bps.MulEach(dst, rates[0], amounts) }); n != 0 {
+		t.Errorf("MulEach() allocates %v times", n)
+	}
+	if n := testing.AllocsPerRun(100, func() { _ = bps.SumSlice(amounts) }); n != 0 {
+		t.Errorf("SumSlice() allocates %v times", n)
+	}
+}
+
+func benchmarkRows(n int) ([]bps.Rate, []bps.Amount) {
+	r := rand.New(rand.NewSource(1))
+	rates := make([]bps.Rate, n)
+	amounts := make([]bps.Amount, n)
+	for i := range rates {
+		rates[i] = bps.RateFromBPS(bps.NewFromDeciBasisPoint(r.Int63n(10000)))
+		amounts[i] = bps.NewAmount(r.Int63n(1e6))
+	}
+	return rates, amounts
+}
+
+func BenchmarkApplyRates_Loop(b *testing.B) {
+	rates, amounts := benchmarkRows(1 << 16)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := range rates {
+			_ = rates[j].Mul(amounts[j]).Round(bps.RoundFloor)
+		}
+	}
+}
+
+func BenchmarkApplyRates(b *testing.B) {
+	rates, amounts := benchmarkRows(1 << 16)
+	dst := make([]bps.Amount, len(amounts))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dst, _ = bps.ApplyRates(dst, rates, amounts, bps.RoundFloor)
+	}
+}
+
+func BenchmarkBatch_ApplyRates(b *testing.B) {
+	rates, amounts := benchmarkRows(1 << 16)
+	dst := make([]bps.Amount, len(amounts))
+	bt := bps.Batch{Workers: 8}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dst, _ = bt.ApplyRates(dst, rates, amounts, bps.RoundFloor)
+	}
+}
+
+func BenchmarkSumSlice(b *testing.B) {
+	_, amounts := benchmarkRows(1 << 16)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = bps.SumSlice(amounts)
+	}
+}
+
+func ExampleBatch() {
+	rates := []bps.Rate{
+		bps.RateFromBPS(bps.NewFromDeciBasisPoint(2645)),
+		bps.RateFromBPS(bps.NewFromPercentage(8)),
+		bps.RateFromBPS(bps.NewFromDeciBasisPoint(2645)),
+	}
+	amounts := []bps.Amount{bps.NewAmount(14999), bps.NewAmount(14999), bps.NewAmount(300)}
+
+	b := bps.Batch{Workers: 4}
+	fees, err := b.ApplyRates(nil, rates, amounts, bps.RoundFloor)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(fees, b.SumSlice(fees))
+	// Output:
+	// [396 1199 7] 1602
+}
@@ -1,29 +1,112 @@
 package bps
 
-import "math/big"
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
 
-// unit is the list of allowed values to set BaseUnit.
-type unit int
+// Unit is the list of allowed values to set BaseUnit.
+type Unit int
 
-// List of values that `unit` can take.
+// List of values that `Unit` can take.
 const (
-	PPB unit = iota + 1
+	PPB Unit = iota + 1
 	PPM
 	DeciBasisPoint
 	HalfBasisPoint
 	BasisPoint
 	Percentage
+	// Unity is one whole amount, that means 100%.
+	Unity
 )
 
+// unitSymbols is the list of symbols to represent `Unit` in a string like "2.645%".
+// The first symbol of each unit is used to format, and all of them are accepted to parse case-insensitively.
+var unitSymbols = []struct {
+	unit    Unit
+	symbols []string
+}{
+	{PPB, []string{"ppb"}},
+	{PPM, []string{"ppm"}},
+	{DeciBasisPoint, []string{"dbp", "dbps"}},
+	{HalfBasisPoint, []string{"hbp", "hbps"}},
+	{BasisPoint, []string{"bp", "bps"}},
+	{Percentage, []string{"%"}},
+}
+
+// String returns the symbol of `u` like "%" or "bp".
+// Unity has no symbol, so it returns an empty string.
+func (u Unit) String() string {
+	for _, us := range unitSymbols {
+		if us.unit == u {
+			return us.symbols[0]
+		}
+	}
+	return ""
+}
+
+// ParseUnit returns the Unit represented by the symbol like "%" or "bp" case-insensitively.
+// It returns an error if the symbol is unknown. Unity has no symbol, so it can't be parsed.
+func ParseUnit(symbol string) (Unit, error) {
+	lower := strings.ToLower(strings.TrimSpace(symbol))
+	for _, us := range unitSymbols {
+		for _, sym := range us.symbols {
+			if lower == sym {
+				return us.unit, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("can't convert %s to Unit", symbol)
+}
+
+// denom returns the number of ppbs in one `u`.
+// The default unit is PPB.
+func (u Unit) denom() int64 {
+	switch u {
+	case PPM:
+		return DenomPPM
+	case DeciBasisPoint:
+		return DenomDeciBasisPoint
+	case HalfBasisPoint:
+		return DenomHalfBasisPoint
+	case BasisPoint:
+		return DenomBasisPoint
+	case Percentage:
+		return DenomPercentage
+	case Unity:
+		return DenomAmount
+	}
+	return 1
+}
+
 // BaseUnit is unit to display *BPS as string via String method.
 // Default is DeciBasisPoint unit, you can update this.
 // But it should be used consistent value in your application.
 var BaseUnit = DeciBasisPoint
 
+// BPS is a basis point value which is represented as an integer count of ppb.
+// The zero value of BPS is 0, and a BPS can be held and copied as a value since the operations never mutate it.
+//
+// The ppb count is stored inline as int64 to avoid allocations in the common case,
+// and it spills to *big.Int only if the count overflows int64.
 type BPS struct {
+	// ppb is the value in ppb. It's used when value is nil.
+	ppb int64
+	// value is the value in ppb when it can't be represented as int64.
+	// It's never mutated after the BPS is made.
 	value *big.Int
 }
 
+// bigValue returns the value in ppb as *big.Int.
+// The result may be shared with `b`, so it must not be mutated.
+func (b *BPS) bigValue() *big.Int {
+	if b.value != nil {
+		return b.value
+	}
+	return big.NewInt(b.ppb)
+}
+
 // String returns the string representation of BaseUnit as generated by *big.Int.String().
 // That means the effective digits is modifiable by BaseUnit.
 func (b *BPS) String() string {
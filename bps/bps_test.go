@@ -89,3 +89,34 @@ func ExampleString() {
 	// 150000
 	// 150000000
 }
+
+func TestParseUnit(t *testing.T) {
+	tests := map[string]struct {
+		symbol  string
+		want    bps.Unit
+		wantErr bool
+	}{
+		"ppb":                                   {"ppb", bps.PPB, false},
+		"ppm":                                   {"PPM", bps.PPM, false},
+		"dbps":                                  {"dbps", bps.DeciBasisPoint, false},
+		"hbp":                                   {" hbp ", bps.HalfBasisPoint, false},
+		"bp":                                    {"bp", bps.BasisPoint, false},
+		"percentage":                            {"%", bps.Percentage, false},
+		"If empty, it should return an error":   {"", 0, true},
+		"If unknown, it should return an error": {"bips", 0, true},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := bps.ParseUnit(tt.symbol)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseUnit() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseUnit() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,61 +1,259 @@
 package bps
 
-import "math/big"
+import (
+	"math"
+	"math/big"
+)
 
 // Abs returns the absolute value of the decimal.
 func (b *BPS) Abs() *BPS {
-	s := nilSafe(b)
-	abs := new(big.Int).Abs(s.value)
-	return newBPS(abs)
+	return new(BPS).SetAbs(b)
 }
 
 // Neg returns -b.
 func (b *BPS) Neg() *BPS {
-	s := nilSafe(b)
-	neg := new(big.Int).Neg(s.value)
-	return newBPS(neg)
+	return new(BPS).SetNeg(b)
 }
 
 // Add returns b + b2.
 func (b *BPS) Add(b2 *BPS) *BPS {
-	added := new(big.Int).Add(b.rawValue(), b2.rawValue())
-	return newBPS(added)
+	return new(BPS).SetAdd(b, b2)
 }
 
 // Sub returns b - b2.
 func (b *BPS) Sub(b2 *BPS) *BPS {
-	subbed := new(big.Int).Sub(b.rawValue(), b2.rawValue())
-	return newBPS(subbed)
+	return new(BPS).SetSub(b, b2)
 }
 
 // Mul returns b * i.
 func (b *BPS) Mul(i int64) *BPS {
-	muled := new(big.Int).Mul(b.rawValue(), big.NewInt(i))
-	return newBPS(muled)
+	return new(BPS).SetMul(b, i)
 }
 
 // Div returns b / i, rounded down to ppm.
 func (b *BPS) Div(i int64) *BPS {
-	dived := new(big.Int).Div(b.rawValue(), big.NewInt(i))
-	return newBPS(dived)
+	return new(BPS).SetDiv(b, i)
+}
+
+// Set sets `b` to x and returns `b`.
+//
+// Set and the other Set* methods are the in-place counterparts of the operations like math/big.
+// They set the receiver `b` to the result and return `b`, so a loop can reuse one instance instead of allocating
+// every intermediate result:
+//
+//	total := new(bps.BPS)
+//	for _, fee := range fees {
+//		total.SetAdd(total, fee)
+//	}
+//
+// The operands may alias `b` and each other. The *big.Int used for values overflowing int64 is never mutated,
+// so copies of `b` made before the call are not affected. That also means only the int64 range is allocation-free.
+func (b *BPS) Set(x *BPS) *BPS {
+	*b = *nilSafe(x)
+	return b
+}
+
+// SetInt64In sets `b` to v counts of `u` and returns `b`.
+func (b *BPS) SetInt64In(u Unit, v int64) *BPS {
+	return b.setInt64(v).SetMul(b, u.denom())
+}
+
+// SetAbs sets `b` to |x| and returns `b`.
+func (b *BPS) SetAbs(x *BPS) *BPS {
+	x = nilSafe(x)
+	if x.value == nil && x.ppb != math.MinInt64 {
+		if x.ppb < 0 {
+			return b.setInt64(-x.ppb)
+		}
+		return b.setInt64(x.ppb)
+	}
+	return b.setBig(new(big.Int).Abs(x.bigValue()))
+}
+
+// SetNeg sets `b` to -x and returns `b`.
+func (b *BPS) SetNeg(x *BPS) *BPS {
+	x = nilSafe(x)
+	if x.value == nil && x.ppb != math.MinInt64 {
+		return b.setInt64(-x.ppb)
+	}
+	return b.setBig(new(big.Int).Neg(x.bigValue()))
+}
+
+// SetAdd sets `b` to x + y and returns `b`.
+func (b *BPS) SetAdd(x, y *BPS) *BPS {
+	x, y = nilSafe(x), nilSafe(y)
+	if v, ok := add64(x, y); ok {
+		return b.setInt64(v)
+	}
+	return b.setBig(new(big.Int).Add(x.bigValue(), y.bigValue()))
+}
+
+// SetSub sets `b` to x - y and returns `b`.
+func (b *BPS) SetSub(x, y *BPS) *BPS {
+	x, y = nilSafe(x), nilSafe(y)
+	if v, ok := sub64(x, y); ok {
+		return b.setInt64(v)
+	}
+	return b.setBig(new(big.Int).Sub(x.bigValue(), y.bigValue()))
+}
+
+// SetMul sets `b` to x * i and returns `b`.
+func (b *BPS) SetMul(x *BPS, i int64) *BPS {
+	x = nilSafe(x)
+	if v, ok := mul64(x, i); ok {
+		return b.setInt64(v)
+	}
+	return b.setBig(new(big.Int).Mul(x.bigValue(), big.NewInt(i)))
+}
+
+// SetDiv sets `b` to x / i rounded down to ppb like Div, and returns `b`.
+func (b *BPS) SetDiv(x *BPS, i int64) *BPS {
+	x = nilSafe(x)
+	if v, ok := div64(x, i); ok {
+		return b.setInt64(v)
+	}
+	return b.setBig(new(big.Int).Div(x.bigValue(), big.NewInt(i)))
 }
 
 func (b *BPS) Cmp(b2 *BPS) int {
-	return b.rawValue().Cmp(b2.rawValue())
+	x, y := nilSafe(b), nilSafe(b2)
+	if x.value == nil && y.value == nil {
+		switch {
+		case x.ppb < y.ppb:
+			return -1
+		case x.ppb > y.ppb:
+			return 1
+		}
+		return 0
+	}
+	return x.bigValue().Cmp(y.bigValue())
 }
 
 func (b *BPS) Equal(b2 *BPS) bool {
 	return b.Cmp(b2) == 0
 }
 
-var zero = &BPS{
-	value: big.NewInt(0),
-}
+var zero = &BPS{}
 
 func (b *BPS) IsZero() bool {
 	return b.Equal(zero)
 }
 
+// Sign returns -1 if b < 0, 0 if b == 0 and +1 if b > 0.
+func (b *BPS) Sign() int {
+	s := nilSafe(b)
+	if s.value != nil {
+		return s.value.Sign()
+	}
+	switch {
+	case s.ppb < 0:
+		return -1
+	case s.ppb > 0:
+		return 1
+	}
+	return 0
+}
+
+// IsNegative reports whether b < 0.
+func (b *BPS) IsNegative() bool {
+	return b.Sign() < 0
+}
+
+// IsPositive reports whether b > 0.
+func (b *BPS) IsPositive() bool {
+	return b.Sign() > 0
+}
+
+// GreaterThan reports whether b > b2.
+func (b *BPS) GreaterThan(b2 *BPS) bool {
+	return b.Cmp(b2) > 0
+}
+
+// GreaterThanOrEqual reports whether b >= b2.
+func (b *BPS) GreaterThanOrEqual(b2 *BPS) bool {
+	return b.Cmp(b2) >= 0
+}
+
+// LessThan reports whether b < b2.
+func (b *BPS) LessThan(b2 *BPS) bool {
+	return b.Cmp(b2) < 0
+}
+
+// LessThanOrEqual reports whether b <= b2.
+func (b *BPS) LessThanOrEqual(b2 *BPS) bool {
+	return b.Cmp(b2) <= 0
+}
+
+// Between reports whether lo <= b <= hi, that means both bounds are inclusive.
+// It always returns false if lo > hi.
+func (b *BPS) Between(lo, hi *BPS) bool {
+	return b.Cmp(lo) >= 0 && b.Cmp(hi) <= 0
+}
+
+// EqualWithin reports whether |b - b2| <= tolerance.
+// It's useful to reconcile values which are allowed to differ by a few ppbs, e.g. EqualWithin(b2, NewFromPPB(big.NewInt(5))).
+// It always returns false if tolerance is negative.
+func (b *BPS) EqualWithin(b2, tolerance *BPS) bool {
+	var diff BPS
+	diff.SetSub(b, b2).SetAbs(&diff)
+	return diff.Cmp(tolerance) <= 0
+}
+
+// add64 returns x + y and true if both are int64 and the result doesn't overflow.
+func add64(x, y *BPS) (int64, bool) {
+	if x.value != nil || y.value != nil {
+		return 0, false
+	}
+	v := x.ppb + y.ppb
+	// the sum overflows iff both operands have the same sign and the sum has the opposite one.
+	return v, (x.ppb^v)&(y.ppb^v) >= 0
+}
+
+// sub64 returns x - y and true if both are int64 and the result doesn't overflow.
+func sub64(x, y *BPS) (int64, bool) {
+	if x.value != nil || y.value != nil {
+		return 0, false
+	}
+	v := x.ppb - y.ppb
+	// the difference overflows iff the operands have different signs and the result has the sign of y.
+	return v, (x.ppb^y.ppb)&(x.ppb^v) >= 0
+}
+
+// mul64 returns x * i and true if x is int64 and the result doesn't overflow.
+func mul64(x *BPS, i int64) (int64, bool) {
+	if x.value != nil {
+		return 0, false
+	}
+	if x.ppb == 0 || i == 0 {
+		return 0, true
+	}
+	if (x.ppb == -1 && i == math.MinInt64) || (i == -1 && x.ppb == math.MinInt64) {
+		return 0, false
+	}
+	v := x.ppb * i
+	return v, v/i == x.ppb
+}
+
+// div64 returns x / i and true if x is int64 and the result doesn't overflow.
+// It implements Euclidean division like big.Int.Div.
+func div64(x *BPS, i int64) (int64, bool) {
+	if i == 0 {
+		panic("division by zero")
+	}
+	if x.value != nil || (x.ppb == math.MinInt64 && i == -1) {
+		return 0, false
+	}
+	q, r := x.ppb/i, x.ppb%i
+	if r < 0 {
+		if i > 0 {
+			q--
+		} else {
+			q++
+		}
+	}
+	return q, true
+}
+
 // FloatString returns the string representation of the amount as generated by *big.Rat.FloatString(prec)
 func (b *BPS) FloatString(prec int) string {
 	return b.Rat().FloatString(prec)
@@ -70,9 +268,10 @@ func Avg(first *BPS, rest ...*BPS) *BPS {
 
 // Sum returns the combined total of the provided first and rest BPS
 func Sum(first *BPS, rest ...*BPS) *BPS {
-	total := first
+	// accumulate into one instance to avoid allocating every intermediate sum.
+	total := new(BPS).Set(first)
 	for _, b := range rest {
-		total = total.Add(b)
+		total.SetAdd(total, b)
 	}
 	return total
 }
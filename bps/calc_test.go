@@ -1,7 +1,9 @@
 package bps_test
 
 import (
+	"math"
 	"math/big"
+	"math/rand"
 	"reflect"
 	"testing"
 
@@ -255,6 +257,223 @@ func TestBPS_Compare(t *testing.T) {
 	})
 }
 
+func TestBPS_Sign(t *testing.T) {
+	huge, _ := new(big.Int).SetString("-100000000000000000000", 10)
+	tests := map[string]struct {
+		b            *bps.BPS
+		want         int
+		wantNegative bool
+		wantPositive bool
+	}{
+		"positive": {
+			bps.NewFromPPB(big.NewInt(1)),
+			1,
+			false,
+			true,
+		},
+		"negative": {
+			bps.NewFromPercentage(-1),
+			-1,
+			true,
+			false,
+		},
+		"zero": {
+			bps.NewFromAmount(0),
+			0,
+			false,
+			false,
+		},
+		"negative beyond int64": {
+			bps.NewFromPPB(huge),
+			-1,
+			true,
+			false,
+		},
+		"nil": {
+			nil,
+			0,
+			false,
+			false,
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.b.Sign(); got != tt.want {
+				t.Errorf("BPS.Sign() = %v, want %v", got, tt.want)
+			}
+			if got := tt.b.IsNegative(); got != tt.wantNegative {
+				t.Errorf("BPS.IsNegative() = %v, want %v", got, tt.wantNegative)
+			}
+			if got := tt.b.IsPositive(); got != tt.wantPositive {
+				t.Errorf("BPS.IsPositive() = %v, want %v", got, tt.wantPositive)
+			}
+		})
+	}
+}
+
+func TestBPS_Comparison(t *testing.T) {
+	tests := map[string]struct {
+		b     *bps.BPS
+		b2    *bps.BPS
+		wantG bool
+		wantE bool
+		wantL bool
+	}{
+		"1% and 2%": {
+			bps.NewFromPercentage(1),
+			bps.NewFromPercentage(2),
+			false,
+			false,
+			true,
+		},
+		"2% and 1%": {
+			bps.NewFromPercentage(2),
+			bps.NewFromPercentage(1),
+			true,
+			false,
+			false,
+		},
+		"100 basis points and 1%": {
+			bps.NewFromBasisPoint(100),
+			bps.NewFromPercentage(1),
+			false,
+			true,
+			false,
+		},
+		"nil and -1 ppb": {
+			nil,
+			bps.NewFromPPB(big.NewInt(-1)),
+			true,
+			false,
+			false,
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.b.GreaterThan(tt.b2); got != tt.wantG {
+				t.Errorf("BPS.GreaterThan() = %v, want %v", got, tt.wantG)
+			}
+			if got := tt.b.GreaterThanOrEqual(tt.b2); got != (tt.wantG || tt.wantE) {
+				t.Errorf("BPS.GreaterThanOrEqual() = %v, want %v", got, tt.wantG || tt.wantE)
+			}
+			if got := tt.b.LessThan(tt.b2); got != tt.wantL {
+				t.Errorf("BPS.LessThan() = %v, want %v", got, tt.wantL)
+			}
+			if got := tt.b.LessThanOrEqual(tt.b2); got != (tt.wantL || tt.wantE) {
+				t.Errorf("BPS.LessThanOrEqual() = %v, want %v", got, tt.wantL || tt.wantE)
+			}
+		})
+	}
+}
+
+func TestBPS_Between(t *testing.T) {
+	tests := map[string]struct {
+		b    *bps.BPS
+		lo   *bps.BPS
+		hi   *bps.BPS
+		want bool
+	}{
+		"inside": {
+			bps.NewFromPercentage(8),
+			bps.NewFromDeciBasisPoint(1500),
+			bps.NewFromPercentage(15),
+			true,
+		},
+		"on the lower bound": {
+			bps.NewFromBasisPoint(150),
+			bps.NewFromDeciBasisPoint(1500),
+			bps.NewFromPercentage(15),
+			true,
+		},
+		"on the upper bound": {
+			bps.NewFromPercentage(15),
+			bps.NewFromDeciBasisPoint(1500),
+			bps.NewFromPercentage(15),
+			true,
+		},
+		"below": {
+			bps.NewFromPercentage(1),
+			bps.NewFromDeciBasisPoint(1500),
+			bps.NewFromPercentage(15),
+			false,
+		},
+		"above": {
+			bps.NewFromPercentage(15).Add(bps.NewFromPPB(big.NewInt(1))),
+			bps.NewFromDeciBasisPoint(1500),
+			bps.NewFromPercentage(15),
+			false,
+		},
+		"reversed bounds": {
+			bps.NewFromPercentage(8),
+			bps.NewFromPercentage(15),
+			bps.NewFromDeciBasisPoint(1500),
+			false,
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.b.Between(tt.lo, tt.hi); got != tt.want {
+				t.Errorf("BPS.Between() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBPS_EqualWithin(t *testing.T) {
+	tests := map[string]struct {
+		b         *bps.BPS
+		b2        *bps.BPS
+		tolerance *bps.BPS
+		want      bool
+	}{
+		"differ by 3 ppbs within 5 ppbs": {
+			bps.NewFromPPB(big.NewInt(26401760)),
+			bps.NewFromPPB(big.NewInt(26401763)),
+			bps.NewFromPPB(big.NewInt(5)),
+			true,
+		},
+		"differ by -5 ppbs within 5 ppbs": {
+			bps.NewFromPPB(big.NewInt(26401765)),
+			bps.NewFromPPB(big.NewInt(26401760)),
+			bps.NewFromPPB(big.NewInt(5)),
+			true,
+		},
+		"differ by 6 ppbs without 5 ppbs": {
+			bps.NewFromPPB(big.NewInt(26401760)),
+			bps.NewFromPPB(big.NewInt(26401766)),
+			bps.NewFromPPB(big.NewInt(5)),
+			false,
+		},
+		"equal values within zero": {
+			bps.NewFromPercentage(1),
+			bps.NewFromBasisPoint(100),
+			nil,
+			true,
+		},
+		"negative tolerance": {
+			bps.NewFromPercentage(1),
+			bps.NewFromPercentage(1),
+			bps.NewFromPPB(big.NewInt(-1)),
+			false,
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.b.EqualWithin(tt.b2, tt.tolerance); got != tt.want {
+				t.Errorf("BPS.EqualWithin() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestSum(t *testing.T) {
 	tests := map[string]struct {
 		first *bps.BPS
@@ -539,3 +758,258 @@ func TestBPS_FloatString(t *testing.T) {
 		})
 	}
 }
+
+func TestBPS_Int64Boundaries(t *testing.T) {
+	t.Parallel()
+
+	values := []int64{math.MinInt64, math.MinInt64 + 1, -3037000500, -1000000007, -2, -1, 0, 1, 2, 3, 1000000007, 3037000500, math.MaxInt64 - 1, math.MaxInt64}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		values = append(values, r.Int63()-r.Int63(), r.Int63n(1<<32)-1<<31)
+	}
+
+	for _, x := range values {
+		bx := big.NewInt(x)
+		a := bps.NewFromPPB(bx)
+		for _, y := range values {
+			by := big.NewInt(y)
+			b := bps.NewFromPPB(by)
+
+			if got, want := a.Add(b).PPBs(), new(big.Int).Add(bx, by); got.Cmp(want) != 0 {
+				t.Errorf("BPS.Add(%d, %d) = %v, want %v", x, y, got, want)
+			}
+			if got, want := a.Sub(b).PPBs(), new(big.Int).Sub(bx, by); got.Cmp(want) != 0 {
+				t.Errorf("BPS.Sub(%d, %d) = %v, want %v", x, y, got, want)
+			}
+			if got, want := a.Mul(y).PPBs(), new(big.Int).Mul(bx, by); got.Cmp(want) != 0 {
+				t.Errorf("BPS.Mul(%d, %d) = %v, want %v", x, y, got, want)
+			}
+			if y != 0 {
+				if got, want := a.Div(y).PPBs(), new(big.Int).Div(bx, by); got.Cmp(want) != 0 {
+					t.Errorf("BPS.Div(%d, %d) = %v, want %v", x, y, got, want)
+				}
+			}
+			if got, want := a.Cmp(b), bx.Cmp(by); got != want {
+				t.Errorf("BPS.Cmp(%d, %d) = %v, want %v", x, y, got, want)
+			}
+			// the results spilled to *big.Int must come back to the same canonical form.
+			if got := a.Add(b).Sub(b); !reflect.DeepEqual(got, a) {
+				t.Errorf("BPS.Add(%d, %d).Sub(%d) = %v, want %v", x, y, y, got.PPBs(), x)
+			}
+		}
+		if got, want := a.Neg().PPBs(), new(big.Int).Neg(bx); got.Cmp(want) != 0 {
+			t.Errorf("BPS.Neg(%d) = %v, want %v", x, got, want)
+		}
+		if got, want := a.Abs().PPBs(), new(big.Int).Abs(bx); got.Cmp(want) != 0 {
+			t.Errorf("BPS.Abs(%d) = %v, want %v", x, got, want)
+		}
+	}
+}
+
+func BenchmarkBPS_Add(b *testing.B) {
+	x := bps.NewFromDeciBasisPoint(2645)
+	y := bps.NewFromPercentage(8)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if x.Add(y).IsZero() {
+			b.Fatal("BPS.Add() should not be zero")
+		}
+	}
+}
+
+func BenchmarkBPS_Sub(b *testing.B) {
+	x := bps.NewFromDeciBasisPoint(2645)
+	y := bps.NewFromPercentage(8)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if x.Sub(y).IsZero() {
+			b.Fatal("BPS.Sub() should not be zero")
+		}
+	}
+}
+
+func BenchmarkBPS_Mul(b *testing.B) {
+	x := bps.NewFromDeciBasisPoint(2645)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if x.Mul(14999).IsZero() {
+			b.Fatal("BPS.Mul() should not be zero")
+		}
+	}
+}
+
+func BenchmarkBPS_Div(b *testing.B) {
+	x := bps.NewFromDeciBasisPoint(2645)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if x.Div(365).IsZero() {
+			b.Fatal("BPS.Div() should not be zero")
+		}
+	}
+}
+
+func BenchmarkBPS_Cmp(b *testing.B) {
+	x := bps.NewFromDeciBasisPoint(2645)
+	y := bps.NewFromPercentage(8)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if x.Cmp(y) >= 0 {
+			b.Fatal("BPS.Cmp() should be negative")
+		}
+	}
+}
+
+func BenchmarkBPS_Add_Big(b *testing.B) {
+	x := bps.NewFromAmount(math.MaxInt64)
+	y := bps.NewFromAmount(math.MaxInt64)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if x.Add(y).IsZero() {
+			b.Fatal("BPS.Add() should not be zero")
+		}
+	}
+}
+
+func BenchmarkSum(b *testing.B) {
+	fees := make([]*bps.BPS, 1000)
+	for i := range fees {
+		fees[i] = bps.NewFromDeciBasisPoint(2645).Mul(int64(i))
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bps.Sum(fees[0], fees[1:]...)
+	}
+}
+
+func TestBPS_Set(t *testing.T) {
+	big1, _ := new(big.Int).SetString("100000000000000000000000", 10)
+	tests := map[string]struct {
+		op   func(z *bps.BPS) *bps.BPS
+		want *bps.BPS
+	}{
+		"Set": {
+			func(z *bps.BPS) *bps.BPS { return z.Set(bps.NewFromPercentage(8)) },
+			bps.NewFromPercentage(8),
+		},
+		"Set nil": {
+			func(z *bps.BPS) *bps.BPS { return z.Set(nil) },
+			bps.NewFromAmount(0),
+		},
+		"SetInt64In": {
+			func(z *bps.BPS) *bps.BPS { return z.SetInt64In(bps.DeciBasisPoint, 2645) },
+			bps.NewFromDeciBasisPoint(2645),
+		},
+		"SetInt64In beyond int64 ppbs": {
+			func(z *bps.BPS) *bps.BPS { return z.SetInt64In(bps.Unity, math.MaxInt64) },
+			bps.NewFromAmount(math.MaxInt64),
+		},
+		"SetAdd": {
+			func(z *bps.BPS) *bps.BPS { return z.SetAdd(bps.NewFromBasisPoint(1), bps.NewFromPercentage(1)) },
+			bps.NewFromPPM(big.NewInt(10100)),
+		},
+		"SetAdd with big values": {
+			func(z *bps.BPS) *bps.BPS { return z.SetAdd(bps.NewFromPPB(big1), bps.NewFromPPB(big1)) },
+			bps.NewFromPPB(new(big.Int).Add(big1, big1)),
+		},
+		"SetSub": {
+			func(z *bps.BPS) *bps.BPS { return z.SetSub(bps.NewFromAmount(1), bps.NewFromPercentage(10)) },
+			bps.NewFromPPM(big.NewInt(900000)),
+		},
+		"SetSub back to int64": {
+			func(z *bps.BPS) *bps.BPS { return z.SetSub(bps.NewFromPPB(big1), bps.NewFromPPB(big1)) },
+			bps.NewFromAmount(0),
+		},
+		"SetMul": {
+			func(z *bps.BPS) *bps.BPS { return z.SetMul(bps.NewFromBasisPoint(1), 5) },
+			bps.NewFromPPM(big.NewInt(500)),
+		},
+		"SetDiv": {
+			func(z *bps.BPS) *bps.BPS { return z.SetDiv(bps.NewFromPPM(big.NewInt(100)), 3) },
+			bps.NewFromPPB(big.NewInt(33333)),
+		},
+		"SetAbs": {
+			func(z *bps.BPS) *bps.BPS { return z.SetAbs(bps.NewFromPercentage(-8)) },
+			bps.NewFromPercentage(8),
+		},
+		"SetNeg": {
+			func(z *bps.BPS) *bps.BPS { return z.SetNeg(bps.NewFromPercentage(8)) },
+			bps.NewFromPercentage(-8),
+		},
+	}
+	for name, tt := range tests {
+		name, tt := name, tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			z := bps.NewFromPPB(big1)
+			got := tt.op(z)
+			if got != z {
+				t.Errorf("BPS.%s() should return the receiver", name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BPS.%s() = %v, want %v", name, got.PPBs(), tt.want.PPBs())
+			}
+		})
+	}
+}
+
+func TestBPS_Set_Aliasing(t *testing.T) {
+	t.Parallel()
+
+	t.Run("the receiver can be an operand", func(t *testing.T) {
+		t.Parallel()
+
+		z := bps.NewFromPercentage(1)
+		z.SetAdd(z, z)
+		z.SetMul(z, 3)
+		z.SetSub(z, bps.NewFromPercentage(1))
+		z.SetNeg(z)
+		if want := bps.NewFromPercentage(-5); !reflect.DeepEqual(z, want) {
+			t.Errorf("got %v, want %v", z.PPBs(), want.PPBs())
+		}
+	})
+
+	t.Run("the copies made before the call are not affected", func(t *testing.T) {
+		t.Parallel()
+
+		z := bps.NewFromAmount(math.MaxInt64).Mul(10)
+		c := *z
+		want := c.PPBs()
+		z.SetAdd(z, z)
+		z.SetInt64In(bps.PPB, 1)
+		if got := c.PPBs(); got.Cmp(want) != 0 {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+}
+
+func BenchmarkBPS_SumByAdd(b *testing.B) {
+	fee := bps.NewFromDeciBasisPoint(2645).Mul(14999)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		total := bps.NewFromAmount(0)
+		for j := 0; j < 1000; j++ {
+			total = total.Add(fee)
+		}
+	}
+}
+
+func BenchmarkBPS_SumBySetAdd(b *testing.B) {
+	fee := bps.NewFromDeciBasisPoint(2645).Mul(14999)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		total := new(bps.BPS)
+		for j := 0; j < 1000; j++ {
+			total.SetAdd(total, fee)
+		}
+	}
+}
+
+func BenchmarkBPS_SetMul(b *testing.B) {
+	z := new(bps.BPS)
+	x := bps.NewFromDeciBasisPoint(2645)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		z.SetMul(x, 14999)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"strconv"
 	"strings"
 )
 
@@ -19,32 +20,7 @@ const (
 
 // NewFromString returns a new BPS from a string representation.
 func NewFromString(value string) (*BPS, error) {
-	var intString string
-	var mul int64 = 1
-
-	parts := strings.Split(value, ".")
-	if len(parts) == 1 {
-		// There is no decimal point, the original string can be just parsed with an int
-		intString = value
-	} else if len(parts) == 2 {
-		// strip the insignificant digits for more accurate comparisons.
-		decimalPart := strings.TrimRight(parts[1], "0")
-		intString = parts[0] + decimalPart
-		if intString == "" && parts[1] != "" {
-			intString = "0"
-		}
-		expInt := len(decimalPart)
-		mul = int64(math.Pow10(expInt))
-	} else {
-		return nil, fmt.Errorf("can't convert %s to BPS: too many .s", value)
-	}
-
-	parsed, ok := new(big.Int).SetString(intString, 10)
-	if !ok {
-		return nil, fmt.Errorf("can't convert %s to BPS", value)
-	}
-
-	return newBPS(parsed).Mul(DenomAmount).Div(mul), nil
+	return ParseBytes([]byte(value))
 }
 
 // MustFromString returns a new BPS from a string representation or panics if NewFromString would have returned an error.
@@ -56,6 +32,97 @@ func MustFromString(value string) *BPS {
 	return b
 }
 
+// NewFromUnitString returns a new BPS from a string representation with a unit symbol like "2.645%", "250bp" or "15 ppm".
+// The symbols are "ppb", "ppm", "dbp", "hbp", "bp" (or "bps") and "%", and they are case-insensitive.
+// A string without a symbol is parsed as an amount like NewFromString, so "0.025" equals "2.5%".
+// A fraction of ppb is rounded down like NewFromString.
+// The spaces around the number and the symbol like " 2.5 % " or " 0.025 " are ignored.
+func NewFromUnitString(value string) (*BPS, error) {
+	num, u := splitUnitSymbol(value)
+	if u == Unity {
+		return NewFromString(num)
+	}
+	s, err := parseScaled(num)
+	if err != nil {
+		return nil, fmt.Errorf("can't convert %s to BPS", value)
+	}
+	v := new(big.Int).Mul(s.value, big.NewInt(u.denom()))
+	return newBPS(quoRound(v, pow10Big(s.scale), RoundFloor)), nil
+}
+
+// splitUnitSymbol splits value into the number part and the unit of the trailing symbol.
+// It returns Unity if value has no symbol. The number part is trimmed of the spaces around it, e.g. "1ppb " or " 5".
+func splitUnitSymbol(value string) (string, Unit) {
+	trimmed := strings.TrimSpace(value)
+	lower := strings.ToLower(trimmed)
+	var (
+		matched Unit = Unity
+		length  int
+	)
+	for _, us := range unitSymbols {
+		for _, sym := range us.symbols {
+			// prefer the longest symbol, e.g. "dbp" rather than "bp"
+			if strings.HasSuffix(lower, sym) && len(sym) > length {
+				matched, length = us.unit, len(sym)
+			}
+		}
+	}
+	if length == 0 {
+		return trimmed, Unity
+	}
+	return strings.TrimSpace(trimmed[:len(trimmed)-length]), matched
+}
+
+// NewFromFloat64 returns a new BPS from a float64 amount, e.g. 0.15 means 15%.
+// `f` is converted via its shortest decimal representation, so 0.1 becomes exactly 0.1 instead of
+// 0.1000000000000000055511151231257827..., and then rounded to ppb by `mode`.
+// It returns an error if `f` is NaN or an infinity.
+func NewFromFloat64(f float64, mode RoundingMode) (*BPS, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("can't convert %v to BPS", f)
+	}
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+	if !ok {
+		return nil, fmt.Errorf("can't convert %v to BPS", f)
+	}
+	return NewFromRat(r, mode), nil
+}
+
+// NewFromBigFloat returns a new BPS from a *big.Float amount rounded to ppb by `mode`.
+// Unlike NewFromFloat64, the exact binary value of `f` is used.
+// It returns an error if `f` is an infinity.
+func NewFromBigFloat(f *big.Float, mode RoundingMode) (*BPS, error) {
+	if f == nil {
+		return newBPS(nil), nil
+	}
+	if f.IsInf() {
+		return nil, fmt.Errorf("can't convert %v to BPS", f)
+	}
+	r, _ := f.Rat(nil)
+	return NewFromRat(r, mode), nil
+}
+
+// NewFromRat returns a new BPS from a rational amount rounded to ppb by `mode`.
+// It is the inverse of Rat.
+func NewFromRat(r *big.Rat, mode RoundingMode) *BPS {
+	if r == nil {
+		return newBPS(nil)
+	}
+	num := new(big.Int).Mul(r.Num(), big.NewInt(DenomAmount))
+	return newBPS(quoRound(num, r.Denom(), mode))
+}
+
+// NewFromRatio returns a new BPS which means numerator / denominator rounded to ppb by `mode`.
+// e.g. NewFromRatio(fee, gross, RoundHalfUp) returns the rate of fee to gross.
+// It returns an error if denominator is zero.
+func NewFromRatio(numerator, denominator int64, mode RoundingMode) (*BPS, error) {
+	if denominator == 0 {
+		return nil, fmt.Errorf("can't convert %d/%d to BPS: division by zero", numerator, denominator)
+	}
+	num := new(big.Int).Mul(big.NewInt(numerator), big.NewInt(DenomAmount))
+	return newBPS(quoRound(num, big.NewInt(denominator), mode)), nil
+}
+
 // NewFromPPB makes new BPS instance from part per billion(ppb)
 func NewFromPPB(ppb *big.Int) *BPS {
 	return newBPS(ppb)
@@ -91,6 +158,37 @@ func NewFromAmount(amt int64) *BPS {
 	return newBPS(big.NewInt(amt)).Mul(DenomAmount)
 }
 
+// NewFromBigDeciBasisPoint makes new BPS instance from deci basis point as *big.Int
+func NewFromBigDeciBasisPoint(deci *big.Int) *BPS {
+	return newBPS(deci).Mul(DenomDeciBasisPoint)
+}
+
+// NewFromBigHalfBasisPoint makes new BPS instance from half basis point as *big.Int
+func NewFromBigHalfBasisPoint(bp *big.Int) *BPS {
+	return newBPS(bp).Mul(DenomHalfBasisPoint)
+}
+
+// NewFromBigBasisPoint makes new BPS instance from basis point as *big.Int
+func NewFromBigBasisPoint(bp *big.Int) *BPS {
+	return newBPS(bp).Mul(DenomBasisPoint)
+}
+
+// NewFromBigPercentage makes new BPS instance from percentage as *big.Int
+func NewFromBigPercentage(per *big.Int) *BPS {
+	return newBPS(per).Mul(DenomPercentage)
+}
+
+// NewFromBigAmount makes new BPS instance from real amount as *big.Int
+func NewFromBigAmount(amt *big.Int) *BPS {
+	return newBPS(amt).Mul(DenomAmount)
+}
+
+// NewFromUint64In makes new BPS instance from an integer count of `u`.
+// It accepts the whole uint64 range without overflow.
+func NewFromUint64In(u Unit, v uint64) *BPS {
+	return newBPS(new(big.Int).SetUint64(v)).Mul(u.denom())
+}
+
 // NewFromBaseUnit makes new BPS instance from BaseUnit value.
 // That means the effective digits is modifiable by BaseUnit.
 func NewFromBaseUnit(v int64) *BPS {
@@ -105,16 +203,39 @@ func NewFromBaseUnit(v int64) *BPS {
 		return NewFromPercentage(v)
 	case PPM:
 		return NewFromPPM(big.NewInt(v))
+	case Unity:
+		return NewFromAmount(v)
 	}
 	// The default unit is PPB
 	return NewFromPPB(big.NewInt(v))
 }
 
+// newBPS makes new BPS instance from ppb.
+// It keeps the canonical form, that means value is used only if ppb can't be represented as int64.
 func newBPS(value *big.Int) *BPS {
 	if value == nil {
-		value = big.NewInt(0)
+		return &BPS{}
+	}
+	if value.IsInt64() {
+		return &BPS{ppb: value.Int64()}
 	}
 	return &BPS{
 		value: new(big.Int).Set(value),
 	}
 }
+
+// setInt64 sets `b` to v ppbs and returns `b`.
+func (b *BPS) setInt64(v int64) *BPS {
+	b.ppb, b.value = v, nil
+	return b
+}
+
+// setBig sets `b` to v ppbs in the canonical form and returns `b`.
+// `b` takes the ownership of v, so v must not be used by the caller after that.
+func (b *BPS) setBig(v *big.Int) *BPS {
+	if v.IsInt64() {
+		return b.setInt64(v.Int64())
+	}
+	b.ppb, b.value = 0, v
+	return b
+}
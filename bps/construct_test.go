@@ -2,6 +2,7 @@ package bps_test
 
 import (
 	"fmt"
+	"math"
 	"math/big"
 	"reflect"
 	"testing"
@@ -275,3 +276,332 @@ func ExampleNewFromBaseUnit() {
 	// 1500000
 	// 150000000
 }
+
+func TestNewFromFloat64(t *testing.T) {
+	tests := map[string]struct {
+		f       float64
+		mode    bps.RoundingMode
+		want    *bps.BPS
+		wantErr bool
+	}{
+		"0.1 is converted to exactly 10 percentages": {
+			0.1,
+			bps.RoundDown,
+			bps.NewFromPercentage(10),
+			false,
+		},
+		"0.02645 is converted to 2645 deci basis points": {
+			0.02645,
+			bps.RoundDown,
+			bps.NewFromDeciBasisPoint(2645),
+			false,
+		},
+		"negative value": {
+			-1.5,
+			bps.RoundDown,
+			bps.NewFromPercentage(-150),
+			false,
+		},
+		"1.5 ppbs rounded half even = 2 ppbs": {
+			0.0000000015,
+			bps.RoundHalfEven,
+			bps.NewFromPPB(big.NewInt(2)),
+			false,
+		},
+		"1.5 ppbs rounded down = 1 ppb": {
+			0.0000000015,
+			bps.RoundDown,
+			bps.NewFromPPB(big.NewInt(1)),
+			false,
+		},
+		"If NaN, it should return an error": {
+			math.NaN(),
+			bps.RoundDown,
+			nil,
+			true,
+		},
+		"If +Inf, it should return an error": {
+			math.Inf(1),
+			bps.RoundDown,
+			nil,
+			true,
+		},
+		"If -Inf, it should return an error": {
+			math.Inf(-1),
+			bps.RoundDown,
+			nil,
+			true,
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := bps.NewFromFloat64(tt.f, tt.mode)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewFromFloat64() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewFromFloat64() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewFromBigFloat(t *testing.T) {
+	tests := map[string]struct {
+		f       *big.Float
+		mode    bps.RoundingMode
+		want    *bps.BPS
+		wantErr bool
+	}{
+		"0.25 is converted to 25 percentages": {
+			big.NewFloat(0.25),
+			bps.RoundDown,
+			bps.NewFromPercentage(25),
+			false,
+		},
+		"binary 0.1 is slightly above 0.1, so it is rounded up to 100,000,001 ppbs": {
+			big.NewFloat(0.1),
+			bps.RoundUp,
+			bps.NewFromPPB(big.NewInt(100000001)),
+			false,
+		},
+		"binary 0.1 rounded half up = 10 percentages": {
+			big.NewFloat(0.1),
+			bps.RoundHalfUp,
+			bps.NewFromPercentage(10),
+			false,
+		},
+		"nil is zero": {
+			nil,
+			bps.RoundDown,
+			bps.NewFromAmount(0),
+			false,
+		},
+		"If infinity, it should return an error": {
+			new(big.Float).SetInf(true),
+			bps.RoundDown,
+			nil,
+			true,
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := bps.NewFromBigFloat(tt.f, tt.mode)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewFromBigFloat() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewFromBigFloat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewFromRat(t *testing.T) {
+	tests := map[string]struct {
+		r    *big.Rat
+		mode bps.RoundingMode
+		want *bps.BPS
+	}{
+		"3/4 is converted to 75 percentages": {
+			big.NewRat(3, 4),
+			bps.RoundDown,
+			bps.NewFromPercentage(75),
+		},
+		"1/3 rounded down = 333,333,333 ppbs": {
+			big.NewRat(1, 3),
+			bps.RoundDown,
+			bps.NewFromPPB(big.NewInt(333333333)),
+		},
+		"2/3 rounded half up = 666,666,667 ppbs": {
+			big.NewRat(2, 3),
+			bps.RoundHalfUp,
+			bps.NewFromPPB(big.NewInt(666666667)),
+		},
+		"-2/3 rounded to floor = -666,666,667 ppbs": {
+			big.NewRat(-2, 3),
+			bps.RoundFloor,
+			bps.NewFromPPB(big.NewInt(-666666667)),
+		},
+		"nil is zero": {
+			nil,
+			bps.RoundDown,
+			bps.NewFromAmount(0),
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got := bps.NewFromRat(tt.r, tt.mode)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewFromRat() = %v, want %v", got, tt.want)
+			}
+			if r := bps.NewFromPercentage(15).Rat(); !bps.NewFromRat(r, tt.mode).Equal(bps.NewFromPercentage(15)) {
+				t.Errorf("NewFromRat() should be the inverse of BPS.Rat(), got %v", r)
+			}
+		})
+	}
+}
+
+func TestNewFromRatio(t *testing.T) {
+	tests := map[string]struct {
+		numerator   int64
+		denominator int64
+		mode        bps.RoundingMode
+		want        *bps.BPS
+		wantErr     bool
+	}{
+		"396 / 14999 rounded down = 26,401,760 ppbs": {
+			396,
+			14999,
+			bps.RoundDown,
+			bps.NewFromPPB(big.NewInt(26401760)),
+			false,
+		},
+		"396 / 14999 rounded up = 26,401,761 ppbs": {
+			396,
+			14999,
+			bps.RoundUp,
+			bps.NewFromPPB(big.NewInt(26401761)),
+			false,
+		},
+		"1 / -8 = -12.5 percentages": {
+			1,
+			-8,
+			bps.RoundDown,
+			bps.NewFromDeciBasisPoint(-12500),
+			false,
+		},
+		"If the denominator is zero, it should return an error": {
+			1,
+			0,
+			bps.RoundDown,
+			nil,
+			true,
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := bps.NewFromRatio(tt.numerator, tt.denominator, tt.mode)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewFromRatio() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewFromRatio() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func ExampleNewFromRatio() {
+	// fee 396 of gross 14999
+	rate, _ := bps.NewFromRatio(396, 14999, bps.RoundHalfUp)
+	fmt.Println(rate.FloatString(9))
+
+	f, _ := bps.NewFromFloat64(0.1, bps.RoundDown)
+	fmt.Println(f.Percentages())
+	// Output:
+	// 0.026401760
+	// 10
+}
+
+func TestNewFromBigInt(t *testing.T) {
+	huge, _ := new(big.Int).SetString("100000000000000000000", 10) // 1e20 overflows int64
+	tests := map[string]struct {
+		got  *bps.BPS
+		want *bps.BPS
+	}{
+		"NewFromBigDeciBasisPoint": {
+			bps.NewFromBigDeciBasisPoint(big.NewInt(2645)),
+			bps.NewFromDeciBasisPoint(2645),
+		},
+		"NewFromBigHalfBasisPoint": {
+			bps.NewFromBigHalfBasisPoint(big.NewInt(-3)),
+			bps.NewFromHalfBasisPoint(-3),
+		},
+		"NewFromBigBasisPoint": {
+			bps.NewFromBigBasisPoint(big.NewInt(15)),
+			bps.NewFromBasisPoint(15),
+		},
+		"NewFromBigPercentage": {
+			bps.NewFromBigPercentage(big.NewInt(8)),
+			bps.NewFromPercentage(8),
+		},
+		"NewFromBigAmount": {
+			bps.NewFromBigAmount(big.NewInt(14999)),
+			bps.NewFromAmount(14999),
+		},
+		"NewFromBigAmount beyond int64": {
+			bps.NewFromBigAmount(huge),
+			bps.NewFromPercentage(1e18).Mul(1e4),
+		},
+		"NewFromBigAmount with nil": {
+			bps.NewFromBigAmount(nil),
+			bps.NewFromAmount(0),
+		},
+		"NewFromUint64In": {
+			bps.NewFromUint64In(bps.HalfBasisPoint, 3),
+			bps.NewFromHalfBasisPoint(3),
+		},
+		"NewFromUint64In with max uint64": {
+			bps.NewFromUint64In(bps.PPB, math.MaxUint64),
+			bps.NewFromPPB(new(big.Int).SetUint64(math.MaxUint64)),
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				t.Errorf("got %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewFromUnitString(t *testing.T) {
+	tests := map[string]struct {
+		arg     string
+		want    *bps.BPS
+		wantErr bool
+	}{
+		"percentage":                    {"2.645%", bps.NewFromDeciBasisPoint(2645), false},
+		"basis point":                   {"250bp", bps.NewFromPercentage(25).Div(10), false},
+		"basis points with a space":     {"250 bps", bps.NewFromBasisPoint(250), false},
+		"deci basis point":              {"2645dbp", bps.NewFromDeciBasisPoint(2645), false},
+		"half basis point":              {"-3 HBP", bps.NewFromHalfBasisPoint(-3), false},
+		"ppm":                           {"15ppm", bps.NewFromPPM(big.NewInt(15)), false},
+		"ppb":                           {"15ppb", bps.NewFromPPB(big.NewInt(15)), false},
+		"no symbol means amount":        {"0.025", bps.NewFromDeciBasisPoint(2500), false},
+		"a fraction of ppb rounds down": {"-0.5ppb", bps.NewFromPPB(big.NewInt(-1)), false},
+		"a trailing space":              {"1ppb ", bps.NewFromPPB(big.NewInt(1)), false},
+		"spaces around a symbol":        {" 2.5 % ", bps.NewFromBasisPoint(250), false},
+		"spaces around an amount":       {" 5 ", bps.NewFromAmount(5), false},
+		"If only a symbol, it should return an error":  {"%", nil, true},
+		"If unknown symbol, it should return an error": {"5bips", nil, true},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := bps.NewFromUnitString(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewFromUnitString() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewFromUnitString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
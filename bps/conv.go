@@ -1,10 +1,20 @@
 package bps
 
-import "math/big"
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ErrOverflow is returned when a BPS can't be represented by the requested integer type.
+var ErrOverflow = errors.New("integer overflow")
 
 // rawValue returns the row value as new big.Int instance
 func (b *BPS) rawValue() *big.Int {
 	s := nilSafe(b)
+	if s.value == nil {
+		return big.NewInt(s.ppb)
+	}
 	return new(big.Int).Set(s.value)
 }
 
@@ -23,6 +33,47 @@ func (b *BPS) Amounts() int64 {
 	return b.Div(DenomAmount).rawValue().Int64()
 }
 
+// AmountsInt64 returns the basis point as an integer amount like Amounts,
+// but it returns ErrOverflow instead of an undefined value if the amount doesn't fit in int64.
+func (b *BPS) AmountsInt64() (int64, error) {
+	return b.Int64In(Unity)
+}
+
+// Int64In returns the basis point as an integer count of `u`.
+// It returns ErrOverflow if the count doesn't fit in int64.
+func (b *BPS) Int64In(u Unit) (int64, error) {
+	c := b.countIn(u)
+	if !c.IsInt64() {
+		return 0, fmt.Errorf("BPS.Int64In: %s: %w", c, ErrOverflow)
+	}
+	return c.Int64(), nil
+}
+
+// Uint64In returns the basis point as an integer count of `u`.
+// It returns ErrOverflow if the count is negative or doesn't fit in uint64.
+func (b *BPS) Uint64In(u Unit) (uint64, error) {
+	c := b.countIn(u)
+	if !c.IsUint64() {
+		return 0, fmt.Errorf("BPS.Uint64In: %s: %w", c, ErrOverflow)
+	}
+	return c.Uint64(), nil
+}
+
+// IsInt64In reports whether the integer count of `u` can be represented as an int64.
+func (b *BPS) IsInt64In(u Unit) bool {
+	return b.countIn(u).IsInt64()
+}
+
+// IsUint64In reports whether the integer count of `u` can be represented as an uint64.
+func (b *BPS) IsUint64In(u Unit) bool {
+	return b.countIn(u).IsUint64()
+}
+
+// countIn returns the basis point as an integer count of `u` that's rounded off like the other conversions.
+func (b *BPS) countIn(u Unit) *big.Int {
+	return b.Div(u.denom()).rawValue()
+}
+
 // Percentages returns the basis point as an integer percentage count.
 func (b *BPS) Percentages() *big.Int {
 	return b.Div(DenomPercentage).rawValue()
@@ -46,7 +97,7 @@ func (b *BPS) DeciBasisPoints() *big.Int {
 // Rat returns a rational number representation of `b`.
 func (b *BPS) Rat() *big.Rat {
 	mul := big.NewInt(DenomAmount)
-	num := nilSafe(b).value
+	num := nilSafe(b).bigValue()
 	return new(big.Rat).SetFrac(num, mul)
 }
 
@@ -57,6 +108,19 @@ func (b *BPS) Float64() (f float64, exact bool) {
 	return b.Rat().Float64()
 }
 
+// Float64In returns the nearest float64 value for `b` in `u`, e.g. 2.645 for 2.645% in Percentage.
+// It's rounded only once from the exact value unlike multiplying the result of Float64,
+// and it doesn't allocate as long as the value fits in 53 bits of ppbs.
+func (b *BPS) Float64In(u Unit) float64 {
+	s := nilSafe(b)
+	// both operands are exact in float64, and a division of float64 is rounded correctly.
+	if s.value == nil && s.ppb <= 1<<53 && s.ppb >= -1<<53 {
+		return float64(s.ppb) / float64(u.denom())
+	}
+	f, _ := new(big.Rat).SetFrac(s.bigValue(), big.NewInt(u.denom())).Float64()
+	return f
+}
+
 // BaseUnitAmounts returns amount representation of BaseUnit as generated.
 // That means the effective digits is modifiable by BaseUnit.
 func (b *BPS) BaseUnitAmounts() *big.Int {
@@ -71,18 +135,17 @@ func (b *BPS) BaseUnitAmounts() *big.Int {
 		return b.Percentages()
 	case PPM:
 		return b.PPMs()
+	case Unity:
+		return b.Div(DenomAmount).rawValue()
 	}
 	// default is PPB
 	return b.rawValue()
 }
 
-// nilSafe returns zero value when b is nil or b.value is nil to avoid nil error.
+// nilSafe returns zero value when b is nil to avoid nil error.
 func nilSafe(b *BPS) *BPS {
 	if b == nil {
 		return zero
 	}
-	if b.value == nil {
-		return zero
-	}
 	return b
 }
@@ -1,7 +1,9 @@
 package bps_test
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"reflect"
 	"testing"
@@ -375,3 +377,152 @@ func ExampleBPS_BaseUnitAmounts() {
 	// 1500
 	// 15
 }
+
+func TestBPS_AmountsInt64(t *testing.T) {
+	overflow, _ := new(big.Int).SetString("9223372036854775808000000000", 10) // (MaxInt64 + 1) amounts
+	tests := map[string]struct {
+		b       *bps.BPS
+		want    int64
+		wantErr bool
+	}{
+		"1,999,999,999 ppbs equals 1 amount": {
+			bps.NewFromPPB(big.NewInt(1999999999)),
+			1,
+			false,
+		},
+		"max int64 amounts": {
+			bps.NewFromAmount(math.MaxInt64),
+			math.MaxInt64,
+			false,
+		},
+		"min int64 amounts": {
+			bps.NewFromAmount(math.MinInt64),
+			math.MinInt64,
+			false,
+		},
+		"If the amount overflows int64, it should return an error": {
+			bps.NewFromPPB(overflow),
+			0,
+			true,
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tt.b.AmountsInt64()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BPS.AmountsInt64() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && !errors.Is(err, bps.ErrOverflow) {
+				t.Errorf("BPS.AmountsInt64() error = %v, want %v", err, bps.ErrOverflow)
+			}
+			if got != tt.want {
+				t.Errorf("BPS.AmountsInt64() = %v, want %v", got, tt.want)
+			}
+			if ok := tt.b.IsInt64In(bps.Unity); ok == tt.wantErr {
+				t.Errorf("BPS.IsInt64In() = %v, want %v", ok, !tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBPS_Int64In(t *testing.T) {
+	tests := map[string]struct {
+		b       *bps.BPS
+		unit    bps.Unit
+		want    int64
+		wantErr bool
+	}{
+		"15 percentages in deci basis points": {
+			bps.NewFromPercentage(15),
+			bps.DeciBasisPoint,
+			15000,
+			false,
+		},
+		"15 percentages in ppbs": {
+			bps.NewFromPercentage(15),
+			bps.PPB,
+			150000000,
+			false,
+		},
+		"max int64 amounts overflow in ppbs": {
+			bps.NewFromAmount(math.MaxInt64),
+			bps.PPB,
+			0,
+			true,
+		},
+		"min int64 basis points overflow in half basis points": {
+			bps.NewFromBasisPoint(math.MinInt64),
+			bps.HalfBasisPoint,
+			0,
+			true,
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tt.b.Int64In(tt.unit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BPS.Int64In() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("BPS.Int64In() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBPS_Uint64In(t *testing.T) {
+	tests := map[string]struct {
+		b       *bps.BPS
+		unit    bps.Unit
+		want    uint64
+		wantErr bool
+	}{
+		"15 percentages in basis points": {
+			bps.NewFromPercentage(15),
+			bps.BasisPoint,
+			1500,
+			false,
+		},
+		"max uint64 ppms": {
+			bps.NewFromUint64In(bps.PPM, math.MaxUint64),
+			bps.PPM,
+			math.MaxUint64,
+			false,
+		},
+		"max uint64 ppms overflow in ppbs": {
+			bps.NewFromUint64In(bps.PPM, math.MaxUint64),
+			bps.PPB,
+			0,
+			true,
+		},
+		"If the value is negative, it should return an error": {
+			bps.NewFromPPB(big.NewInt(-1)),
+			bps.PPB,
+			0,
+			true,
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tt.b.Uint64In(tt.unit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BPS.Uint64In() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("BPS.Uint64In() = %v, want %v", got, tt.want)
+			}
+			if ok := tt.b.IsUint64In(tt.unit); ok == tt.wantErr {
+				t.Errorf("BPS.IsUint64In() = %v, want %v", ok, !tt.wantErr)
+			}
+		})
+	}
+}
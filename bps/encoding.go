@@ -0,0 +1,122 @@
+package bps
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// make sure that the *BPS implements some interfaces.
+var _ interface {
+	xml.Unmarshaler
+	xml.UnmarshalerAttr
+} = (*BPS)(nil)
+
+// The marshalers have value receivers, so that a BPS field is marshalled in MarshalFormat as well as a *BPS field
+// instead of MarshalText. The encoders skip or write null for a nil *BPS without calling them.
+var _ interface {
+	xml.Marshaler
+	xml.MarshalerAttr
+} = BPS{}
+
+// Format is the list of representations of BPS in YAML and XML.
+type Format int
+
+// List of values that `Format` can take.
+const (
+	// FormatDecimal is the shortest exact decimal amount like "0.02645".
+	FormatDecimal Format = iota
+	// FormatPercent is the exact percentage with the symbol like "2.645%".
+	FormatPercent
+	// FormatBaseUnit is the integer count of BaseUnit like String, e.g. 2645 in DeciBasisPoint.
+	// The digits below BaseUnit are lost, so it round-trips only the values on the BaseUnit grid.
+	FormatBaseUnit
+)
+
+// MarshalFormat is the representation of *BPS in YAML and XML.
+// Default is FormatDecimal, you can update this.
+// Both marshalling and unmarshalling use it, so it should be used consistent value in your application like BaseUnit.
+var MarshalFormat = FormatDecimal
+
+// formatString returns the representation of `b` in MarshalFormat.
+func (b *BPS) formatString() string {
+	switch MarshalFormat {
+	case FormatPercent:
+		return b.UnitString(Percentage)
+	case FormatBaseUnit:
+		return nilSafe(b).String()
+	}
+	text, _ := nilSafe(b).AppendText(nil)
+	return string(text)
+}
+
+// parseFormat sets `b` to the representation in MarshalFormat.
+// Except FormatBaseUnit, it accepts any unit symbol by NewFromUnitString, e.g. "2.645%" is read in FormatDecimal too.
+func (b *BPS) parseFormat(s string) error {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return errors.New("BPS: no data")
+	}
+	if MarshalFormat == FormatBaseUnit {
+		n, ok := new(big.Int).SetString(s, 10)
+		if !ok {
+			return fmt.Errorf("can't convert %s to BPS: not an integer count of %s", s, BaseUnit)
+		}
+		b.setBig(n.Mul(n, big.NewInt(BaseUnit.denom())))
+		return nil
+	}
+	v, err := NewFromUnitString(s)
+	if err != nil {
+		return err
+	}
+	*b = *v
+	return nil
+}
+
+// MarshalYAML implements the yaml.Marshaler interface of gopkg.in/yaml.v2 and gopkg.in/yaml.v3 in MarshalFormat.
+// FormatBaseUnit is marshalled as an integer, and the others as a string.
+func (b BPS) MarshalYAML() (interface{}, error) {
+	if MarshalFormat == FormatBaseUnit {
+		v := b.BaseUnitAmounts()
+		if v.IsInt64() {
+			return v.Int64(), nil
+		}
+	}
+	return b.formatString(), nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface of gopkg.in/yaml.v2 in MarshalFormat.
+// gopkg.in/yaml.v3 also supports this form of the interface.
+func (b *BPS) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	return b.parseFormat(s)
+}
+
+// MarshalXML implements the xml.Marshaler interface in MarshalFormat, that means `b` is the text of the element.
+func (b BPS) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(b.formatString(), start)
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface in MarshalFormat.
+func (b *BPS) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var s string
+	if err := d.DecodeElement(&s, &start); err != nil {
+		return err
+	}
+	return b.parseFormat(s)
+}
+
+// MarshalXMLAttr implements the xml.MarshalerAttr interface in MarshalFormat.
+func (b BPS) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return xml.Attr{Name: name, Value: b.formatString()}, nil
+}
+
+// UnmarshalXMLAttr implements the xml.UnmarshalerAttr interface in MarshalFormat.
+func (b *BPS) UnmarshalXMLAttr(attr xml.Attr) error {
+	return b.parseFormat(attr.Value)
+}
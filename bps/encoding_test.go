@@ -0,0 +1,252 @@
+package bps_test
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"go.mercari.io/go-bps/bps"
+)
+
+// setMarshalFormat sets bps.MarshalFormat during the test, so the test must not be parallel.
+func setMarshalFormat(t *testing.T, f bps.Format) {
+	t.Helper()
+	backup := bps.MarshalFormat
+	bps.MarshalFormat = f
+	t.Cleanup(func() { bps.MarshalFormat = backup })
+}
+
+type xmlFee struct {
+	XMLName xml.Name `xml:"fee"`
+	Rate    *bps.BPS `xml:"rate,attr"`
+	Cap     *bps.BPS `xml:"cap"`
+	Floor   *bps.BPS `xml:"floor,omitempty"`
+}
+
+func TestBPS_MarshalXML(t *testing.T) {
+	tests := map[string]struct {
+		format bps.Format
+		want   string
+	}{
+		"decimal":   {bps.FormatDecimal, `<fee rate="0.02645"><cap>0.1</cap></fee>`},
+		"percent":   {bps.FormatPercent, `<fee rate="2.645%"><cap>10%</cap></fee>`},
+		"base unit": {bps.FormatBaseUnit, `<fee rate="2645"><cap>10000</cap></fee>`},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			setMarshalFormat(t, tt.format)
+			fee := xmlFee{Rate: bps.NewFromDeciBasisPoint(2645), Cap: bps.NewFromPercentage(10)}
+			data, err := xml.Marshal(fee)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("xml.Marshal() = %s, want %s", data, tt.want)
+			}
+			var got xmlFee
+			if err := xml.Unmarshal(data, &got); err != nil {
+				t.Fatal(err)
+			}
+			got.XMLName = xml.Name{}
+			fee.XMLName = xml.Name{}
+			if !reflect.DeepEqual(got, fee) {
+				t.Errorf("xml.Unmarshal() = %+v, want %+v", got, fee)
+			}
+		})
+	}
+}
+
+func TestBPS_UnmarshalXML(t *testing.T) {
+	tests := map[string]struct {
+		format  bps.Format
+		data    string
+		want    *bps.BPS
+		wantErr bool
+	}{
+		"decimal": {
+			bps.FormatDecimal, `<fee rate="0.02645"><cap> 0.1 </cap></fee>`, bps.NewFromPercentage(10), false,
+		},
+		"decimal accepts a unit symbol": {
+			bps.FormatDecimal, `<fee rate="264.5bp"><cap>10%</cap></fee>`, bps.NewFromPercentage(10), false,
+		},
+		"base unit": {
+			bps.FormatBaseUnit, `<fee rate="2645"><cap>-10000</cap></fee>`, bps.NewFromPercentage(-10), false,
+		},
+		"If base unit is not an integer, it should return an error": {
+			bps.FormatBaseUnit, `<fee rate="2645"><cap>1.5</cap></fee>`, nil, true,
+		},
+		"If invalid attribute, it should return an error": {
+			bps.FormatDecimal, `<fee rate="x"><cap>0.1</cap></fee>`, nil, true,
+		},
+		"If empty element, it should return an error": {
+			bps.FormatPercent, `<fee rate="1%"><cap></cap></fee>`, nil, true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			setMarshalFormat(t, tt.format)
+			var got xmlFee
+			err := xml.Unmarshal([]byte(tt.data), &got)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("xml.Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got.Cap, tt.want) {
+				t.Errorf("xml.Unmarshal() = %v, want %v", got.Cap, tt.want)
+			}
+		})
+	}
+}
+
+func TestBPS_MarshalYAML(t *testing.T) {
+	tests := map[string]struct {
+		format bps.Format
+		b      *bps.BPS
+		want   interface{}
+	}{
+		"decimal":                     {bps.FormatDecimal, bps.NewFromDeciBasisPoint(2645), "0.02645"},
+		"percent":                     {bps.FormatPercent, bps.NewFromDeciBasisPoint(2645), "2.645%"},
+		"base unit":                   {bps.FormatBaseUnit, bps.NewFromDeciBasisPoint(2645), int64(2645)},
+		"base unit overflowing int64": {bps.FormatBaseUnit, bps.NewFromPPB(new(big.Int).Lsh(big.NewInt(1), 100)), "126765060022822940149670320"},
+		"zero":                        {bps.FormatPercent, &bps.BPS{}, "0%"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			setMarshalFormat(t, tt.format)
+			got, err := tt.b.MarshalYAML()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("BPS.MarshalYAML() = %#v, want %#v", got, tt.want)
+			}
+
+			// a YAML decoder decodes a scalar into string
+			var b bps.BPS
+			err = b.UnmarshalYAML(func(v interface{}) error {
+				*v.(*string) = fmt.Sprint(got)
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := tt.b
+			if tt.format == bps.FormatBaseUnit {
+				want = tt.b.TruncateTo(bps.BaseUnit)
+			}
+			if !b.Equal(want) {
+				t.Errorf("BPS.UnmarshalYAML() = %v, want %v", b.PPBs(), want.PPBs())
+			}
+		})
+	}
+}
+
+func TestBPS_UnmarshalYAML_Error(t *testing.T) {
+	var b bps.BPS
+	err := b.UnmarshalYAML(func(v interface{}) error {
+		return fmt.Errorf("cannot unmarshal !!seq into string")
+	})
+	if err == nil {
+		t.Error("BPS.UnmarshalYAML() should return the error of unmarshal")
+	}
+	err = b.UnmarshalYAML(func(v interface{}) error {
+		*v.(*string) = "2.645 percent"
+		return nil
+	})
+	if err == nil {
+		t.Error("BPS.UnmarshalYAML() should return an error for invalid value")
+	}
+}
+
+// yamlMarshaler is the yaml.Marshaler interface of gopkg.in/yaml.v2 and gopkg.in/yaml.v3.
+type yamlMarshaler interface {
+	MarshalYAML() (interface{}, error)
+}
+
+func TestBPS_MarshalValue(t *testing.T) {
+	type fee struct {
+		XMLName xml.Name `xml:"fee"`
+		Rate    bps.BPS  `xml:"rate,attr"`
+		Cap     bps.BPS  `xml:"cap"`
+		Floor   *bps.BPS `xml:"floor,omitempty"`
+	}
+	tests := map[string]struct {
+		format   bps.Format
+		wantXML  string
+		wantYAML interface{}
+	}{
+		"decimal":   {bps.FormatDecimal, `<fee rate="0.02645"><cap>0.1</cap></fee>`, "0.02645"},
+		"percent":   {bps.FormatPercent, `<fee rate="2.645%"><cap>10%</cap></fee>`, "2.645%"},
+		"base unit": {bps.FormatBaseUnit, `<fee rate="2645"><cap>10000</cap></fee>`, int64(2645)},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			setMarshalFormat(t, tt.format)
+			v := fee{Rate: *bps.NewFromDeciBasisPoint(2645), Cap: *bps.NewFromPercentage(10)}
+
+			// a struct passed by value isn't addressable, so it must not fall back to MarshalText
+			data, err := xml.Marshal(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.wantXML {
+				t.Errorf("xml.Marshal() = %s, want %s", data, tt.wantXML)
+			}
+			if data2, _ := xml.Marshal(&v); string(data2) != string(data) {
+				t.Errorf("xml.Marshal() of a pointer = %s, want %s", data2, data)
+			}
+			var got fee
+			if err := xml.Unmarshal(data, &got); err != nil {
+				t.Fatal(err)
+			}
+			if !got.Rate.Equal(&v.Rate) || !got.Cap.Equal(&v.Cap) || got.Floor != nil {
+				t.Errorf("xml.Unmarshal() = %+v, want %+v", got, v)
+			}
+
+			// the YAML encoders look for the interface on the value as is
+			m, ok := interface{}(v.Rate).(yamlMarshaler)
+			if !ok {
+				t.Fatal("BPS doesn't implement yaml.Marshaler")
+			}
+			y, err := m.MarshalYAML()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if y != tt.wantYAML {
+				t.Errorf("BPS.MarshalYAML() = %#v, want %#v", y, tt.wantYAML)
+			}
+			var b bps.BPS
+			err = b.UnmarshalYAML(func(v interface{}) error {
+				*v.(*string) = fmt.Sprint(y)
+				return nil
+			})
+			if err != nil || !b.Equal(&v.Rate) {
+				t.Errorf("BPS.UnmarshalYAML() = %v, %v, want %v", b.PPBs(), err, v.Rate.PPBs())
+			}
+		})
+	}
+}
+
+func ExampleMarshalFormat() {
+	type Fee struct {
+		Rate *bps.BPS `xml:"rate,attr"`
+		Cap  *bps.BPS `xml:"cap"`
+	}
+	fee := Fee{Rate: bps.NewFromDeciBasisPoint(2645), Cap: bps.NewFromPercentage(10)}
+
+	data, _ := xml.Marshal(fee)
+	fmt.Println(string(data))
+
+	// backup
+	f := bps.MarshalFormat
+	bps.MarshalFormat = bps.FormatPercent
+	data, _ = xml.Marshal(fee)
+	fmt.Println(string(data))
+	// restore
+	bps.MarshalFormat = f
+	// Output:
+	// <Fee rate="0.02645"><cap>0.1</cap></Fee>
+	// <Fee rate="2.645%"><cap>10%</cap></Fee>
+}
@@ -0,0 +1,57 @@
+package bps
+
+import "fmt"
+
+// make sure that the types implement expvar.Var and Gauge.
+// This file doesn't import expvar since it registers the handler to http.DefaultServeMux,
+// but expvar.Var is the same interface as fmt.Stringer.
+var (
+	_ fmt.Stringer = (*Var)(nil)
+	_ fmt.Stringer = (*AtomicBPS)(nil)
+	_ fmt.Stringer = (*Accumulator)(nil)
+	_ Gauge        = (*BPS)(nil)
+	_ Gauge        = (*AtomicBPS)(nil)
+)
+
+// Var is BPS which implements the expvar.Var interface with the canonical decimal like 0.02645 as a JSON number.
+// *BPS also satisfies expvar.Var by String, but the representation depends on BaseUnit and truncates the fraction,
+// so convert it like (*bps.Var)(b) to publish it, which refers to the same value:
+//
+//	expvar.Publish("take_rate", (*bps.Var)(takeRate))
+//
+// expvar reads the value from other goroutines, so it must not be changed after publishing. Use AtomicBPS for a value which changes.
+type Var BPS
+
+// String implements the expvar.Var interface. It returns the canonical decimal, which is a valid JSON number.
+func (v *Var) String() string {
+	return (*BPS)(v).CanonicalString()
+}
+
+// String returns the canonical decimal of the current value like "0.02645".
+// It implements the expvar.Var interface since it's a valid JSON number, so *AtomicBPS can be published as a running total:
+//
+//	var feeTotal bps.AtomicBPS
+//	expvar.Publish("fee_total", &feeTotal)
+func (a *AtomicBPS) String() string {
+	return a.Load().CanonicalString()
+}
+
+// Float64In returns the nearest float64 value for the current value in `u` like BPS.Float64In.
+func (a *AtomicBPS) Float64In(u Unit) float64 {
+	return a.Load().Float64In(u)
+}
+
+// Gauge is the interface to read a value as a float64 in a unit, which metric libraries take as a gauge.
+// It's implemented by *BPS and *AtomicBPS.
+type Gauge interface {
+	Float64In(u Unit) float64
+}
+
+// GaugeFunc returns a function which reads `g` in `u`, so that it can be passed to metric libraries as it is, e.g.
+//
+//	prometheus.NewGaugeFunc(opts, bps.GaugeFunc(&feeTotal, bps.Unity))
+func GaugeFunc(g Gauge, u Unit) func() float64 {
+	return func() float64 {
+		return g.Float64In(u)
+	}
+}
@@ -0,0 +1,178 @@
+package bps_test
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"go.mercari.io/go-bps/bps"
+)
+
+func TestVar_String(t *testing.T) {
+	tests := map[string]struct {
+		b    *bps.BPS
+		want string
+	}{
+		"2.645%":   {bps.NewFromDeciBasisPoint(2645), "0.02645"},
+		"1 ppb":    {bps.NewFromPPB(big.NewInt(1)), "0.000000001"},
+		"negative": {bps.NewFromAmount(-3), "-3"},
+		"zero":     {&bps.BPS{}, "0"},
+		"big":      {bps.NewFromPPB(new(big.Int).Lsh(big.NewInt(1), 70)), "1180591620717.411303424"},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got := (*bps.Var)(tt.b).String()
+			if got != tt.want {
+				t.Errorf("Var.String() = %v, want %v", got, tt.want)
+			}
+			var n json.Number
+			if err := json.Unmarshal([]byte(got), &n); err != nil {
+				t.Errorf("Var.String() = %v is not a JSON number: %v", got, err)
+			}
+		})
+	}
+}
+
+func TestAtomicBPS_String(t *testing.T) {
+	t.Parallel()
+
+	var a bps.AtomicBPS
+	if got := a.String(); got != "0" {
+		t.Errorf("AtomicBPS.String() = %v, want 0", got)
+	}
+	a.Add(bps.NewFromDeciBasisPoint(2645))
+	if got := a.String(); got != "0.02645" {
+		t.Errorf("AtomicBPS.String() = %v, want 0.02645", got)
+	}
+}
+
+// publishRuns makes the names of expvar unique per run of TestPublish, because expvar.Publish panics on a reused name
+// with -count 2 or more.
+var publishRuns int64
+
+func TestPublish(t *testing.T) {
+	t.Parallel()
+
+	prefix := fmt.Sprintf("bps_test_%d_", atomic.AddInt64(&publishRuns, 1))
+	takeRate := bps.NewFromDeciBasisPoint(2645)
+	var feeTotal bps.AtomicBPS
+	acc := bps.NewAccumulator(bps.BasisPoint)
+	expvar.Publish(prefix+"take_rate", (*bps.Var)(takeRate))
+	expvar.Publish(prefix+"fee_total", &feeTotal)
+	expvar.Publish(prefix+"accumulator", acc)
+	feeTotal.Add(bps.NewFromAmount(396))
+	acc.AddRat(big.NewRat(1, 3))
+
+	rec := httptest.NewRecorder()
+	expvar.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/debug/vars", nil))
+	var vars map[string]json.RawMessage
+	if err := json.Unmarshal(rec.Body.Bytes(), &vars); err != nil {
+		t.Fatalf("/debug/vars is not valid JSON: %v", err)
+	}
+	want := map[string]string{
+		"take_rate":   `0.02645`,
+		"fee_total":   `396`,
+		"accumulator": `"1/3 bp"`,
+	}
+	for name, w := range want {
+		if got := string(vars[prefix+name]); got != w {
+			t.Errorf("/debug/vars %s = %s, want %s", prefix+name, got, w)
+		}
+	}
+}
+
+func TestAccumulator_String(t *testing.T) {
+	t.Parallel()
+
+	// expvar calls String concurrently with the updates
+	acc := bps.NewAccumulator(bps.Unity)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				acc.AddRat(big.NewRat(1, 3))
+				acc.Emit()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				var s string
+				if err := json.Unmarshal([]byte(acc.String()), &s); err != nil {
+					t.Errorf("Accumulator.String() = %s is not a JSON string: %v", acc.String(), err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got, want := acc.String(), `"1/3 amount"`; got != want {
+		t.Errorf("Accumulator.String() = %s, want %s", got, want)
+	}
+}
+
+func TestBPS_Float64In(t *testing.T) {
+	tests := map[string]struct {
+		b    *bps.BPS
+		u    bps.Unit
+		want float64
+	}{
+		"2.645% in percentage":   {bps.NewFromDeciBasisPoint(2645), bps.Percentage, 2.645},
+		"2.645% in basis points": {bps.NewFromDeciBasisPoint(2645), bps.BasisPoint, 264.5},
+		"2.645% in amount":       {bps.NewFromDeciBasisPoint(2645), bps.Unity, 0.02645},
+		"1 ppb in ppm":           {bps.NewFromPPB(big.NewInt(1)), bps.PPM, 0.001},
+		"negative":               {bps.NewFromPercentage(-15), bps.Percentage, -15},
+		"nil":                    {nil, bps.Percentage, 0},
+		"beyond 53 bits":         {bps.NewFromPPB(big.NewInt(math.MaxInt64)), bps.PPB, 9223372036854775807},
+		"big":                    {bps.NewFromPPB(new(big.Int).Lsh(big.NewInt(1), 70)), bps.PPB, 1 << 70},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.b.Float64In(tt.u); got != tt.want {
+				t.Errorf("BPS.Float64In() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGaugeFunc(t *testing.T) {
+	t.Parallel()
+
+	var a bps.AtomicBPS
+	f := bps.GaugeFunc(&a, bps.BasisPoint)
+	if got := f(); got != 0 {
+		t.Errorf("GaugeFunc() = %v, want 0", got)
+	}
+	a.Add(bps.NewFromDeciBasisPoint(2645))
+	if got := f(); got != 264.5 {
+		t.Errorf("GaugeFunc() = %v, want 264.5", got)
+	}
+	if got := bps.GaugeFunc(bps.NewFromPercentage(3), bps.Percentage)(); got != 3 {
+		t.Errorf("GaugeFunc() = %v, want 3", got)
+	}
+}
+
+func ExampleGaugeFunc() {
+	var feeTotal bps.AtomicBPS
+	feeTotal.Add(bps.NewFromDeciBasisPoint(2645))
+	feeTotal.Add(bps.NewFromDeciBasisPoint(1355))
+
+	// e.g. prometheus.NewGaugeFunc(opts, gauge)
+	gauge := bps.GaugeFunc(&feeTotal, bps.Percentage)
+	fmt.Println(gauge())
+	// Output:
+	// 4
+}
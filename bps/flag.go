@@ -0,0 +1,91 @@
+package bps
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// make sure that the *FlagValue implements some interfaces.
+var _ interface {
+	flag.Getter
+	fmt.Scanner
+} = (*FlagValue)(nil)
+
+// FlagValue is BPS which implements the flag.Value and fmt.Scanner interfaces.
+// *BPS can't implement them itself because its Set and Scan methods are for the in-place operation and the database,
+// so convert it like (*bps.FlagValue)(b), which refers to the same value:
+//
+//	threshold := bps.NewFromPercentage(5)
+//	fs.Var((*bps.FlagValue)(threshold), "threshold", "alert threshold")
+//
+// The input is parsed by NewFromUnitString, so it accepts a unit symbol like "2.5%" or "250bp".
+type FlagValue BPS
+
+// Set implements the flag.Value interface.
+func (f *FlagValue) Set(s string) error {
+	b, err := NewFromUnitString(strings.TrimSpace(s))
+	if err != nil {
+		return err
+	}
+	*f = FlagValue(*b)
+	return nil
+}
+
+// String implements the flag.Value interface. It returns the percentage like "2.5%".
+func (f *FlagValue) String() string {
+	return (*BPS)(f).UnitString(Percentage)
+}
+
+// Get implements the flag.Getter interface. It returns *BPS.
+func (f *FlagValue) Get() interface{} {
+	return (*BPS)(f)
+}
+
+// Scan implements the fmt.Scanner interface, so fmt.Sscan can read a value like "2.5%" into *FlagValue.
+// It supports the verbs %v, %s, %f, %g and %e.
+func (f *FlagValue) Scan(state fmt.ScanState, verb rune) error {
+	switch verb {
+	case 'v', 's', 'f', 'g', 'e':
+	default:
+		return fmt.Errorf("BPS.Scan: invalid verb %%%c", verb)
+	}
+	state.SkipSpace()
+	token, err := state.Token(false, func(r rune) bool {
+		return r != ' ' && r != '\t' && r != '\r' && r != '\n'
+	})
+	if err != nil {
+		return err
+	}
+	return f.Set(string(token))
+}
+
+// Flag defines a BPS flag with the name, default value and usage on flag.CommandLine like flag.Int.
+// The return value is the address of a BPS that stores the value of the flag.
+func Flag(name string, value *BPS, usage string) *BPS {
+	p := new(BPS)
+	FlagVar(p, name, value, usage)
+	return p
+}
+
+// FlagVar defines a BPS flag with the name, default value and usage on flag.CommandLine like flag.IntVar.
+// The argument p points to a BPS in which to store the value of the flag.
+func FlagVar(p *BPS, name string, value *BPS, usage string) {
+	p.Set(value)
+	flag.Var((*FlagValue)(p), name, usage)
+}
+
+// FromEnv returns the value of the environment variable named by the key parsed by NewFromUnitString.
+// It returns def if the variable is unset or empty.
+func FromEnv(key string, def *BPS) (*BPS, error) {
+	v := strings.TrimSpace(os.Getenv(key))
+	if v == "" {
+		return new(BPS).Set(def), nil
+	}
+	b, err := NewFromUnitString(v)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", key, err)
+	}
+	return b, nil
+}
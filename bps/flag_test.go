@@ -0,0 +1,155 @@
+package bps_test
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"go.mercari.io/go-bps/bps"
+)
+
+func TestFlagValue_Set(t *testing.T) {
+	tests := map[string]struct {
+		arg     string
+		want    *bps.BPS
+		wantErr bool
+	}{
+		"percentage":  {"2.5%", bps.NewFromBasisPoint(250), false},
+		"basis point": {" 250bp ", bps.NewFromBasisPoint(250), false},
+		"amount":      {"-0.025", bps.NewFromBasisPoint(-250), false},
+		"If invalid, it should return an error and keep the value": {"2.5 percent", bps.NewFromPercentage(1), true},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got := bps.NewFromPercentage(1)
+			err := (*bps.FlagValue)(got).Set(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FlagValue.Set() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FlagValue.Set() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlagValue_FlagSet(t *testing.T) {
+	t.Parallel()
+
+	threshold := bps.NewFromPercentage(5)
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var((*bps.FlagValue)(threshold), "threshold", "alert threshold")
+	if got := fs.Lookup("threshold").DefValue; got != "5%" {
+		t.Errorf("DefValue = %v, want 5%%", got)
+	}
+	if err := fs.Parse([]string{"-threshold", "150bp"}); err != nil {
+		t.Fatal(err)
+	}
+	if !threshold.Equal(bps.NewFromBasisPoint(150)) {
+		t.Errorf("threshold = %v, want 150bp", threshold.UnitString(bps.BasisPoint))
+	}
+	getter := fs.Lookup("threshold").Value.(flag.Getter)
+	if got := getter.Get().(*bps.BPS); got != threshold {
+		t.Errorf("FlagValue.Get() = %p, want %p", got, threshold)
+	}
+	if err := fs.Parse([]string{"-threshold", "x"}); err == nil {
+		t.Error("FlagSet.Parse() should return an error")
+	}
+}
+
+// flagRuns makes the flag names unique per run of TestFlag, because flag.CommandLine panics on a redefined flag
+// with -count 2 or more.
+var flagRuns int64
+
+func TestFlag(t *testing.T) {
+	t.Parallel()
+
+	name := fmt.Sprintf("bps-test-flag-%d", atomic.AddInt64(&flagRuns, 1))
+	p := bps.Flag(name, bps.NewFromPercentage(1), "usage")
+	var q bps.BPS
+	bps.FlagVar(&q, name+"-var", nil, "usage")
+	if !p.Equal(bps.NewFromPercentage(1)) || !q.IsZero() {
+		t.Errorf("Flag() = %v, FlagVar() = %v, want the defaults", p, &q)
+	}
+	if err := flag.Set(name, "2.5%"); err != nil {
+		t.Fatal(err)
+	}
+	if err := flag.Set(name+"-var", "3hbp"); err != nil {
+		t.Fatal(err)
+	}
+	if !p.Equal(bps.NewFromBasisPoint(250)) || !q.Equal(bps.NewFromHalfBasisPoint(3)) {
+		t.Errorf("Flag() = %v, FlagVar() = %v", p, &q)
+	}
+}
+
+func TestFlagValue_Scan(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		format  string
+		want    *bps.BPS
+		wantErr bool
+	}{
+		"percentage": {" 2.5% ", "%v", bps.NewFromBasisPoint(250), false},
+		"amount":     {"0.025", "%f", bps.NewFromBasisPoint(250), false},
+		"If invalid verb, it should return an error":  {"2.5%", "%d", nil, true},
+		"If invalid value, it should return an error": {"2.5x", "%v", nil, true},
+		"If empty, it should return an error":         {"", "%v", nil, true},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var got bps.BPS
+			_, err := fmt.Sscanf(tt.input, tt.format, (*bps.FlagValue)(&got))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FlagValue.Scan() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !got.Equal(tt.want) {
+				t.Errorf("FlagValue.Scan() = %v, want %v", &got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("BPS_TEST_SET", "250bp")
+	t.Setenv("BPS_TEST_EMPTY", "")
+	t.Setenv("BPS_TEST_INVALID", "250 bips")
+
+	got, err := bps.FromEnv("BPS_TEST_SET", nil)
+	if err != nil || !got.Equal(bps.NewFromPercentage(2).Add(bps.NewFromBasisPoint(50))) {
+		t.Errorf("FromEnv() = %v, %v", got, err)
+	}
+	def := bps.NewFromPercentage(1)
+	for _, key := range []string{"BPS_TEST_EMPTY", "BPS_TEST_UNSET"} {
+		got, err := bps.FromEnv(key, def)
+		if err != nil || !reflect.DeepEqual(got, def) || got == def {
+			t.Errorf("FromEnv(%s) = %v, %v, want a copy of the default", key, got, err)
+		}
+	}
+	if _, err := bps.FromEnv("BPS_TEST_INVALID", def); err == nil {
+		t.Error("FromEnv() should return an error")
+	}
+}
+
+func ExampleFlagValue() {
+	var b bps.BPS
+	fmt.Sscan("2.5%", (*bps.FlagValue)(&b))
+	fmt.Println(b.BasisPoints())
+
+	fs := flag.NewFlagSet("example", flag.ExitOnError)
+	threshold := bps.NewFromPercentage(5)
+	fs.Var((*bps.FlagValue)(threshold), "threshold", "alert threshold")
+	fs.Parse([]string{"-threshold", "150bp"})
+	fmt.Println((*bps.FlagValue)(threshold))
+	// Output:
+	// 250
+	// 1.5%
+}
@@ -0,0 +1,114 @@
+package bps
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// AppendText appends the amount representation of `b` to dst and returns the extended buffer.
+// The representation is the shortest exact decimal like "0.02645", which can be parsed by ParseBytes and NewFromString.
+// It implements the encoding.TextAppender interface introduced in Go 1.24.
+func (b *BPS) AppendText(dst []byte) ([]byte, error) {
+	return b.AppendFloat(dst, Unity, -1), nil
+}
+
+// AppendFloat appends the decimal representation of `b` in `u` to dst and returns the extended buffer,
+// e.g. AppendFloat(dst, Percentage, 2) appends "2.65" for 2.645%.
+// The number of digits after the decimal point is prec, and the last digit is rounded half away from zero
+// like FloatString. If prec is negative, it uses the smallest number of digits necessary to represent `b` exactly.
+//
+// It doesn't allocate except growing dst as long as the value fits in int64 ppbs.
+func (b *BPS) AppendFloat(dst []byte, u Unit, prec int) []byte {
+	s := nilSafe(b)
+	mul, scale := decimalScale(u.denom())
+	if s.value == nil {
+		mag := uint64(s.ppb)
+		if s.ppb < 0 {
+			mag = uint64(-s.ppb)
+		}
+		if mag <= math.MaxUint64/mul {
+			return appendDecimal(dst, s.ppb < 0, mag*mul, scale, prec)
+		}
+	}
+
+	r := new(big.Rat).SetFrac(s.bigValue(), big.NewInt(u.denom()))
+	if prec >= 0 {
+		return append(dst, r.FloatString(prec)...)
+	}
+	f := r.FloatString(scale)
+	if strings.Contains(f, ".") {
+		f = strings.TrimRight(strings.TrimRight(f, "0"), ".")
+	}
+	return append(dst, f...)
+}
+
+// UnitString returns the exact decimal representation of `b` in `u` followed by the symbol of `u`, e.g. "2.645%" or "264.5bp".
+// It can be parsed by NewFromUnitString.
+func (b *BPS) UnitString(u Unit) string {
+	var buf [32]byte
+	return string(append(b.AppendFloat(buf[:0], u, -1), u.String()...))
+}
+
+// decimalScale returns mul and scale such that n / d = n * mul / 10^scale.
+// d must be a divisor of a power of 10, which all the denominators of Unit are.
+func decimalScale(d int64) (mul uint64, scale int) {
+	p := int64(1)
+	for p%d != 0 {
+		p *= 10
+		scale++
+	}
+	return uint64(p / d), scale
+}
+
+// appendDecimal appends mag / 10^scale with prec fractional digits to dst.
+func appendDecimal(dst []byte, neg bool, mag uint64, scale, prec int) []byte {
+	if prec < 0 {
+		for scale > 0 && mag%10 == 0 {
+			mag /= 10
+			scale--
+		}
+		prec = scale
+	}
+	if prec < scale {
+		p := pow10(scale - prec)
+		q, r := mag/p, mag%p
+		// round half away from zero
+		if r >= p-r {
+			q++
+		}
+		mag, scale = q, prec
+	}
+
+	if neg {
+		dst = append(dst, '-')
+	}
+	p := pow10(scale)
+	dst = strconv.AppendUint(dst, mag/p, 10)
+	if prec == 0 {
+		return dst
+	}
+
+	dst = append(dst, '.')
+	var buf [20]byte
+	frac := mag % p
+	for i := scale - 1; i >= 0; i-- {
+		buf[i] = byte('0' + frac%10)
+		frac /= 10
+	}
+	dst = append(dst, buf[:scale]...)
+	for i := scale; i < prec; i++ {
+		dst = append(dst, '0')
+	}
+	return dst
+}
+
+// pow10 returns 10^n for 0 <= n <= 19.
+func pow10(n int) uint64 {
+	p := uint64(1)
+	for i := 0; i < n; i++ {
+		p *= 10
+	}
+	return p
+}
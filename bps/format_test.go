@@ -0,0 +1,238 @@
+package bps_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"go.mercari.io/go-bps/bps"
+)
+
+func TestBPS_AppendFloat(t *testing.T) {
+	huge, _ := new(big.Int).SetString("-123456789012345678901234567890", 10)
+	tests := map[string]struct {
+		b    *bps.BPS
+		unit bps.Unit
+		prec int
+		want string
+	}{
+		"2.645% in percentage with 2 digits": {
+			bps.NewFromDeciBasisPoint(2645),
+			bps.Percentage,
+			2,
+			"2.65",
+		},
+		"-2.645% in percentage with 2 digits": {
+			bps.NewFromDeciBasisPoint(-2645),
+			bps.Percentage,
+			2,
+			"-2.65",
+		},
+		"2.645% in percentage with the smallest digits": {
+			bps.NewFromDeciBasisPoint(2645),
+			bps.Percentage,
+			-1,
+			"2.645",
+		},
+		"2.645% in amount with the smallest digits": {
+			bps.NewFromDeciBasisPoint(2645),
+			bps.Unity,
+			-1,
+			"0.02645",
+		},
+		"2.645% in basis point with 5 digits": {
+			bps.NewFromDeciBasisPoint(2645),
+			bps.BasisPoint,
+			5,
+			"264.50000",
+		},
+		"2.645% in half basis point": {
+			bps.NewFromDeciBasisPoint(2645),
+			bps.HalfBasisPoint,
+			-1,
+			"529",
+		},
+		"1 deci basis point in half basis point": {
+			bps.NewFromDeciBasisPoint(1),
+			bps.HalfBasisPoint,
+			-1,
+			"0.2",
+		},
+		"1 ppb in ppm": {
+			bps.NewFromPPB(big.NewInt(1)),
+			bps.PPM,
+			-1,
+			"0.001",
+		},
+		"1 ppb in ppb with 3 digits": {
+			bps.NewFromPPB(big.NewInt(1)),
+			bps.PPB,
+			3,
+			"1.000",
+		},
+		"-1 ppb in amount rounded to zero keeps the sign like FloatString": {
+			bps.NewFromPPB(big.NewInt(-1)),
+			bps.Unity,
+			2,
+			"-0.00",
+		},
+		"zero": {
+			bps.NewFromAmount(0),
+			bps.Unity,
+			-1,
+			"0",
+		},
+		"nil": {
+			nil,
+			bps.Percentage,
+			1,
+			"0.0",
+		},
+		"min int64 ppbs in half basis point": {
+			bps.NewFromPPB(big.NewInt(math.MinInt64)),
+			bps.HalfBasisPoint,
+			-1,
+			"-184467440737095.51616",
+		},
+		"beyond int64 ppbs in amount": {
+			bps.NewFromPPB(huge),
+			bps.Unity,
+			-1,
+			"-123456789012345678901.23456789",
+		},
+		"beyond int64 ppbs in amount with 2 digits": {
+			bps.NewFromPPB(huge),
+			bps.Unity,
+			2,
+			"-123456789012345678901.23",
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if got := string(tt.b.AppendFloat([]byte(nil), tt.unit, tt.prec)); got != tt.want {
+				t.Errorf("BPS.AppendFloat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBPS_AppendText(t *testing.T) {
+	t.Parallel()
+
+	got, err := bps.NewFromDeciBasisPoint(2645).AppendText([]byte("rate="))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "rate=0.02645"; string(got) != want {
+		t.Errorf("BPS.AppendText() = %s, want %s", got, want)
+	}
+}
+
+// randomBPS returns a random BPS in various magnitudes including beyond int64.
+func randomBPS(r *rand.Rand) *bps.BPS {
+	v := new(big.Int).Rand(r, new(big.Int).Lsh(big.NewInt(1), uint(r.Intn(100)+1)))
+	if r.Intn(2) == 0 {
+		v.Neg(v)
+	}
+	return bps.NewFromPPB(v)
+}
+
+func TestBPS_AppendFloat_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	units := []bps.Unit{bps.PPB, bps.PPM, bps.DeciBasisPoint, bps.HalfBasisPoint, bps.BasisPoint, bps.Percentage, bps.Unity}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		b := randomBPS(r)
+
+		text, _ := b.AppendText(nil)
+		got, err := bps.ParseBytes(text)
+		if err != nil {
+			t.Errorf("ParseBytes(%s) error = %v", text, err)
+			continue
+		}
+		if !got.Equal(b) {
+			t.Errorf("ParseBytes(%s) = %v, want %v", text, got.PPBs(), b.PPBs())
+		}
+
+		prec := r.Intn(12)
+		if got, want := string(b.AppendFloat(nil, bps.Unity, prec)), b.FloatString(prec); got != want {
+			t.Errorf("BPS.AppendFloat(%v, Unity, %d) = %s, want %s", b.PPBs(), prec, got, want)
+		}
+
+		u := units[r.Intn(len(units))]
+		want := new(big.Rat).Quo(b.Rat(), bps.NewFromUint64In(u, 1).Rat())
+		if got, ok := new(big.Rat).SetString(string(b.AppendFloat(nil, u, -1))); !ok || got.Cmp(want) != 0 {
+			t.Errorf("BPS.AppendFloat(%v, %v, -1) = %v, want %v", b.PPBs(), u, got, want)
+		}
+	}
+}
+
+func TestBPS_AppendFloat_Allocs(t *testing.T) {
+	b := bps.NewFromDeciBasisPoint(-2645)
+	buf := make([]byte, 0, 64)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = b.AppendFloat(buf[:0], bps.Percentage, 4)
+		buf, _ = b.AppendText(buf[:0])
+	})
+	if allocs != 0 {
+		t.Errorf("BPS.AppendFloat() allocates %v times, want 0", allocs)
+	}
+}
+
+func ExampleBPS_AppendFloat() {
+	b := bps.NewFromDeciBasisPoint(2645)
+	buf := make([]byte, 0, 32)
+	buf = b.AppendFloat(buf, bps.Percentage, 2)
+	buf = append(buf, "% ("...)
+	buf = b.AppendFloat(buf, bps.BasisPoint, -1)
+	buf = append(buf, " bp)"...)
+	fmt.Println(string(buf))
+	// Output:
+	// 2.65% (264.5 bp)
+}
+
+func BenchmarkBPS_AppendFloat(b *testing.B) {
+	x := bps.NewFromDeciBasisPoint(2645)
+	buf := make([]byte, 0, 64)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = x.AppendFloat(buf[:0], bps.Percentage, 4)
+	}
+}
+
+func BenchmarkBPS_FloatString(b *testing.B) {
+	x := bps.NewFromDeciBasisPoint(2645)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = x.FloatString(6)
+	}
+}
+
+func TestBPS_UnitString(t *testing.T) {
+	t.Parallel()
+
+	b := bps.NewFromDeciBasisPoint(2645)
+	want := map[bps.Unit]string{
+		bps.PPB:            "26450000ppb",
+		bps.PPM:            "26450ppm",
+		bps.DeciBasisPoint: "2645dbp",
+		bps.HalfBasisPoint: "529hbp",
+		bps.BasisPoint:     "264.5bp",
+		bps.Percentage:     "2.645%",
+		bps.Unity:          "0.02645",
+	}
+	for u, w := range want {
+		got := b.UnitString(u)
+		if got != w {
+			t.Errorf("BPS.UnitString(%d) = %v, want %v", u, got, w)
+		}
+		if parsed, err := bps.NewFromUnitString(got); err != nil || !parsed.Equal(b) {
+			t.Errorf("NewFromUnitString(%v) = %v, %v, want %v", got, parsed, err, b)
+		}
+	}
+}
@@ -0,0 +1,336 @@
+package bps
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// make sure that Interval implements some interfaces.
+var _ interface {
+	fmt.Stringer
+	driver.Valuer
+	encoding.TextMarshaler
+} = Interval{}
+
+var _ interface {
+	sql.Scanner
+	encoding.TextUnmarshaler
+} = (*Interval)(nil)
+
+// Interval is a range of BPS like "between 1.5% and 15%, inclusive".
+// Each bound can be open or closed, and a nil bound means unbounded, which is always treated as open.
+// The zero value of Interval is (-inf, +inf), that means it contains every value.
+type Interval struct {
+	Lower     *BPS
+	Upper     *BPS
+	LowerOpen bool
+	UpperOpen bool
+}
+
+// ClosedInterval returns [lower, upper].
+func ClosedInterval(lower, upper *BPS) Interval {
+	return Interval{Lower: lower, Upper: upper}
+}
+
+// OpenInterval returns (lower, upper).
+func OpenInterval(lower, upper *BPS) Interval {
+	return Interval{Lower: lower, Upper: upper, LowerOpen: true, UpperOpen: true}
+}
+
+// ClosedOpenInterval returns [lower, upper).
+func ClosedOpenInterval(lower, upper *BPS) Interval {
+	return Interval{Lower: lower, Upper: upper, UpperOpen: true}
+}
+
+// OpenClosedInterval returns (lower, upper].
+func OpenClosedInterval(lower, upper *BPS) Interval {
+	return Interval{Lower: lower, Upper: upper, LowerOpen: true}
+}
+
+// lowerOpen reports whether the lower bound is open including unbounded.
+func (i Interval) lowerOpen() bool {
+	return i.LowerOpen || i.Lower == nil
+}
+
+// upperOpen reports whether the upper bound is open including unbounded.
+func (i Interval) upperOpen() bool {
+	return i.UpperOpen || i.Upper == nil
+}
+
+// IsEmpty reports whether `i` contains no value, e.g. [15%, 1.5%] or [1.5%, 1.5%).
+// Values are integer counts of ppb, so an interval without a ppb between the bounds like (1ppb, 2ppb) is also empty.
+func (i Interval) IsEmpty() bool {
+	lo, hi := i.closedBounds()
+	return lo != nil && hi != nil && lo.Cmp(hi) > 0
+}
+
+// closedBounds returns the smallest and the largest values in `i` on the ppb grid, or nil if unbounded.
+// An open bound is moved inward by one ppb.
+func (i Interval) closedBounds() (lo, hi *BPS) {
+	onePPB := &BPS{ppb: 1}
+	if i.Lower != nil {
+		lo = i.Lower
+		if i.LowerOpen {
+			lo = i.Lower.Add(onePPB)
+		}
+	}
+	if i.Upper != nil {
+		hi = i.Upper
+		if i.UpperOpen {
+			hi = i.Upper.Sub(onePPB)
+		}
+	}
+	return lo, hi
+}
+
+// Contains reports whether `b` is in `i`.
+func (i Interval) Contains(b *BPS) bool {
+	if i.Lower != nil {
+		c := b.Cmp(i.Lower)
+		if c < 0 || (c == 0 && i.LowerOpen) {
+			return false
+		}
+	}
+	if i.Upper != nil {
+		c := b.Cmp(i.Upper)
+		if c > 0 || (c == 0 && i.UpperOpen) {
+			return false
+		}
+	}
+	return true
+}
+
+// Clamp returns the nearest value to `b` in `i`, so that Contains of the result is always true unless `i` is empty.
+// An open bound is approached by one ppb, e.g. Clamp of 20% in [1.5%, 15%) is 15% - 1 ppb.
+// If `i` is empty, it returns `b` as is.
+func (i Interval) Clamp(b *BPS) *BPS {
+	if i.IsEmpty() {
+		return new(BPS).Set(b)
+	}
+	lo, hi := i.closedBounds()
+	if lo != nil && b.Cmp(lo) < 0 {
+		return new(BPS).Set(lo)
+	}
+	if hi != nil && b.Cmp(hi) > 0 {
+		return new(BPS).Set(hi)
+	}
+	return new(BPS).Set(b)
+}
+
+// Intersect returns the intersection of `i` and i2, which may be empty.
+func (i Interval) Intersect(i2 Interval) Interval {
+	var res Interval
+	res.Lower, res.LowerOpen = i.Lower, i.LowerOpen
+	if cmpLower(i2, i) > 0 {
+		res.Lower, res.LowerOpen = i2.Lower, i2.LowerOpen
+	}
+	res.Upper, res.UpperOpen = i.Upper, i.UpperOpen
+	if cmpUpper(i2, i) < 0 {
+		res.Upper, res.UpperOpen = i2.Upper, i2.UpperOpen
+	}
+	return res
+}
+
+// Overlaps reports whether `i` and i2 have any value in common.
+func (i Interval) Overlaps(i2 Interval) bool {
+	return !i.Intersect(i2).IsEmpty()
+}
+
+// Union returns the union of `i` and i2 and true if it can be represented as one interval,
+// that means they overlap or are adjacent like [1%, 2%) and [2%, 3%].
+// Otherwise it returns false.
+func (i Interval) Union(i2 Interval) (Interval, bool) {
+	switch {
+	case i.IsEmpty():
+		return i2, true
+	case i2.IsEmpty():
+		return i, true
+	}
+
+	// the lower one must reach the upper one without a gap
+	lo, hi := i, i2
+	if cmpLower(hi, lo) < 0 {
+		lo, hi = hi, lo
+	}
+	if lo.Upper != nil && hi.Lower != nil {
+		c := lo.Upper.Cmp(hi.Lower)
+		if c < 0 || (c == 0 && lo.UpperOpen && hi.LowerOpen) {
+			return Interval{}, false
+		}
+	}
+
+	res := lo
+	if cmpUpper(hi, lo) > 0 {
+		res.Upper, res.UpperOpen = hi.Upper, hi.UpperOpen
+	}
+	return res, true
+}
+
+// cmpLower compares the lower bounds of `i` and i2, and returns -1 if `i` starts before i2.
+func cmpLower(i, i2 Interval) int {
+	switch {
+	case i.Lower == nil && i2.Lower == nil:
+		return 0
+	case i.Lower == nil:
+		return -1
+	case i2.Lower == nil:
+		return 1
+	}
+	if c := i.Lower.Cmp(i2.Lower); c != 0 {
+		return c
+	}
+	// a closed bound starts before an open one at the same value
+	return boolCmp(i.LowerOpen, i2.LowerOpen)
+}
+
+// cmpUpper compares the upper bounds of `i` and i2, and returns +1 if `i` ends after i2.
+func cmpUpper(i, i2 Interval) int {
+	switch {
+	case i.Upper == nil && i2.Upper == nil:
+		return 0
+	case i.Upper == nil:
+		return 1
+	case i2.Upper == nil:
+		return -1
+	}
+	if c := i.Upper.Cmp(i2.Upper); c != 0 {
+		return c
+	}
+	// a closed bound ends after an open one at the same value
+	return boolCmp(i2.UpperOpen, i.UpperOpen)
+}
+
+// boolCmp returns +1 if only a is true, -1 if only b is true, otherwise 0.
+func boolCmp(a, b bool) int {
+	switch {
+	case a && !b:
+		return 1
+	case !a && b:
+		return -1
+	}
+	return 0
+}
+
+// String returns the interval notation of `i` in percentage like "[1.5%, 15%)".
+// An unbounded bound is represented as "-inf" or "+inf".
+func (i Interval) String() string {
+	var sb strings.Builder
+	if i.lowerOpen() {
+		sb.WriteByte('(')
+	} else {
+		sb.WriteByte('[')
+	}
+	if i.Lower == nil {
+		sb.WriteString("-inf")
+	} else {
+		sb.WriteString(i.Lower.UnitString(Percentage))
+	}
+	sb.WriteString(", ")
+	if i.Upper == nil {
+		sb.WriteString("+inf")
+	} else {
+		sb.WriteString(i.Upper.UnitString(Percentage))
+	}
+	if i.upperOpen() {
+		sb.WriteByte(')')
+	} else {
+		sb.WriteByte(']')
+	}
+	return sb.String()
+}
+
+// ParseInterval returns a new Interval from the interval notation like "[1.5%, 15%)" or "(-inf, 0.15]".
+// The bounds are parsed by NewFromUnitString, and "-inf", "+inf" or "inf" means unbounded.
+// It returns an error if the lower bound is greater than the upper bound like "[2%, 1%]", which is likely a mistake.
+// The bounds of the same value like "[1%, 1%)" are accepted even if the interval is empty.
+func ParseInterval(value string) (Interval, error) {
+	s := strings.TrimSpace(value)
+	if len(s) < 2 {
+		return Interval{}, fmt.Errorf("can't convert %s to Interval", value)
+	}
+
+	var i Interval
+	switch s[0] {
+	case '[':
+	case '(':
+		i.LowerOpen = true
+	default:
+		return Interval{}, fmt.Errorf("can't convert %s to Interval: missing the lower bracket", value)
+	}
+	switch s[len(s)-1] {
+	case ']':
+	case ')':
+		i.UpperOpen = true
+	default:
+		return Interval{}, fmt.Errorf("can't convert %s to Interval: missing the upper bracket", value)
+	}
+
+	parts := strings.Split(s[1:len(s)-1], ",")
+	if len(parts) != 2 {
+		return Interval{}, fmt.Errorf("can't convert %s to Interval: it must have 2 bounds", value)
+	}
+	var err error
+	if i.Lower, err = parseBound(parts[0], "-inf"); err != nil {
+		return Interval{}, fmt.Errorf("can't convert %s to Interval: %w", value, err)
+	}
+	if i.Upper, err = parseBound(parts[1], "+inf"); err != nil {
+		return Interval{}, fmt.Errorf("can't convert %s to Interval: %w", value, err)
+	}
+	if (i.Lower == nil && !i.LowerOpen) || (i.Upper == nil && !i.UpperOpen) {
+		return Interval{}, fmt.Errorf("can't convert %s to Interval: an unbounded bound must be open", value)
+	}
+	if i.Lower != nil && i.Upper != nil && i.Lower.Cmp(i.Upper) > 0 {
+		return Interval{}, fmt.Errorf("can't convert %s to Interval: the lower bound is greater than the upper bound", value)
+	}
+	return i, nil
+}
+
+// parseBound returns a bound of Interval, or nil if s means the infinity.
+func parseBound(s, inf string) (*BPS, error) {
+	s = strings.TrimSpace(s)
+	if strings.EqualFold(s, inf) || strings.EqualFold(s, "inf") {
+		return nil, nil
+	}
+	return NewFromUnitString(s)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// It uses the interval notation like String.
+func (i Interval) MarshalText() ([]byte, error) {
+	return []byte(i.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (i *Interval) UnmarshalText(text []byte) error {
+	n, err := ParseInterval(string(text))
+	if err != nil {
+		return err
+	}
+	*i = n
+	return nil
+}
+
+// Value implements the driver.Valuer interface for database serialization.
+// It uses the interval notation like String.
+func (i Interval) Value() (driver.Value, error) {
+	return i.String(), nil
+}
+
+// Scan implements the sql.Scanner interface for database deserialization.
+// It accepts the interval notation as string or []byte.
+func (i *Interval) Scan(value interface{}) error {
+	if i == nil {
+		return errors.New("Interval.Scan: nil receiver")
+	}
+	switch v := value.(type) {
+	case string:
+		return i.UnmarshalText([]byte(v))
+	case []byte:
+		return i.UnmarshalText(v)
+	}
+	return errors.New("Interval.Scan: invalid type, supporting only string or []byte")
+}
@@ -0,0 +1,335 @@
+package bps_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"go.mercari.io/go-bps/bps"
+)
+
+func mustInterval(s string) bps.Interval {
+	i, err := bps.ParseInterval(s)
+	if err != nil {
+		panic(err)
+	}
+	return i
+}
+
+func TestParseInterval(t *testing.T) {
+	tests := map[string]struct {
+		value   string
+		want    bps.Interval
+		wantErr bool
+	}{
+		"closed-open": {
+			"[1.5%, 15%)",
+			bps.ClosedOpenInterval(bps.NewFromDeciBasisPoint(1500), bps.NewFromPercentage(15)),
+			false,
+		},
+		"open-closed with various units": {
+			"(150bp,0.15]",
+			bps.OpenClosedInterval(bps.NewFromBasisPoint(150), bps.NewFromPercentage(15)),
+			false,
+		},
+		"unbounded lower": {
+			" (-inf, 15%] ",
+			bps.Interval{Upper: bps.NewFromPercentage(15), LowerOpen: true},
+			false,
+		},
+		"unbounded upper": {
+			"[0, inf)",
+			bps.Interval{Lower: bps.NewFromAmount(0), UpperOpen: true},
+			false,
+		},
+		"If an unbounded bound is closed, it should return an error": {
+			"[-inf, 15%]",
+			bps.Interval{},
+			true,
+		},
+		"If missing a bracket, it should return an error": {
+			"1.5%, 15%]",
+			bps.Interval{},
+			true,
+		},
+		"If 3 bounds, it should return an error": {
+			"[1%, 2%, 3%]",
+			bps.Interval{},
+			true,
+		},
+		"If invalid bound, it should return an error": {
+			"[1%, x]",
+			bps.Interval{},
+			true,
+		},
+		"If the bounds are inverted, it should return an error": {
+			"[2%, 1%]",
+			bps.Interval{},
+			true,
+		},
+		"the same bounds are accepted": {
+			"[1%, 1%)",
+			bps.ClosedOpenInterval(bps.NewFromPercentage(1), bps.NewFromPercentage(1)),
+			false,
+		},
+		"If empty, it should return an error": {
+			"",
+			bps.Interval{},
+			true,
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := bps.ParseInterval(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseInterval() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseInterval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInterval_String(t *testing.T) {
+	tests := map[string]struct {
+		i    bps.Interval
+		want string
+	}{
+		"closed-open": {
+			bps.ClosedOpenInterval(bps.NewFromDeciBasisPoint(1500), bps.NewFromPercentage(15)),
+			"[1.5%, 15%)",
+		},
+		"open": {
+			bps.OpenInterval(bps.NewFromPPB(big.NewInt(1)), bps.NewFromDeciBasisPoint(2645)),
+			"(0.0000001%, 2.645%)",
+		},
+		"zero value": {
+			bps.Interval{},
+			"(-inf, +inf)",
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got := tt.i.String()
+			if got != tt.want {
+				t.Errorf("Interval.String() = %v, want %v", got, tt.want)
+			}
+			if parsed := mustInterval(got); parsed.String() != got {
+				t.Errorf("ParseInterval(%v) = %v, want the same", got, parsed)
+			}
+		})
+	}
+}
+
+func TestInterval_Contains(t *testing.T) {
+	tests := map[string]struct {
+		i    string
+		b    *bps.BPS
+		want bool
+	}{
+		"inside":                    {"[1.5%, 15%)", bps.NewFromPercentage(8), true},
+		"on the closed lower bound": {"[1.5%, 15%)", bps.NewFromDeciBasisPoint(1500), true},
+		"on the open upper bound":   {"[1.5%, 15%)", bps.NewFromPercentage(15), false},
+		"on the open lower bound":   {"(1.5%, 15%]", bps.NewFromDeciBasisPoint(1500), false},
+		"on the closed upper bound": {"(1.5%, 15%]", bps.NewFromPercentage(15), true},
+		"below":                     {"[1.5%, 15%]", bps.NewFromPercentage(1), false},
+		"above":                     {"[1.5%, 15%]", bps.NewFromPercentage(16), false},
+		"unbounded":                 {"(-inf, +inf)", bps.NewFromPercentage(-1000), true},
+		"empty":                     {"[1%, 1%)", bps.NewFromPercentage(1), false},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if got := mustInterval(tt.i).Contains(tt.b); got != tt.want {
+				t.Errorf("Interval.Contains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInterval_Clamp(t *testing.T) {
+	tests := map[string]struct {
+		i    string
+		b    *bps.BPS
+		want *bps.BPS
+	}{
+		"inside":                     {"[1.5%, 15%)", bps.NewFromPercentage(8), bps.NewFromPercentage(8)},
+		"below the closed bound":     {"[1.5%, 15%)", bps.NewFromPercentage(1), bps.NewFromDeciBasisPoint(1500)},
+		"above the open bound":       {"[1.5%, 15%)", bps.NewFromPercentage(20), bps.NewFromPercentage(15).Sub(bps.NewFromPPB(big.NewInt(1)))},
+		"on the open lower bound":    {"(1.5%, 15%)", bps.NewFromDeciBasisPoint(1500), bps.NewFromDeciBasisPoint(1500).Add(bps.NewFromPPB(big.NewInt(1)))},
+		"above the closed bound":     {"(-inf, 15%]", bps.NewFromPercentage(20), bps.NewFromPercentage(15)},
+		"empty":                      {"[1.5%, 1.5%)", bps.NewFromPercentage(20), bps.NewFromPercentage(20)},
+		"no ppb between the bounds":  {"(1ppb, 2ppb)", bps.NewFromPPB(big.NewInt(1)), bps.NewFromPPB(big.NewInt(1))},
+		"one ppb between the bounds": {"(1ppb, 3ppb)", bps.NewFromPercentage(1), bps.NewFromPPB(big.NewInt(2))},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			i := mustInterval(tt.i)
+			got := i.Clamp(tt.b)
+			if !got.Equal(tt.want) {
+				t.Errorf("Interval.Clamp() = %v, want %v", got.PPBs(), tt.want.PPBs())
+			}
+			if !i.IsEmpty() && !i.Contains(got) {
+				t.Errorf("Interval.Clamp() = %v, which is not in %v", got.PPBs(), i)
+			}
+		})
+	}
+}
+
+func TestInterval_IsEmpty(t *testing.T) {
+	tests := map[string]struct {
+		i    bps.Interval
+		want bool
+	}{
+		"inverted":           {bps.ClosedInterval(bps.NewFromPercentage(15), bps.NewFromPercentage(1)), true},
+		"the same with open": {bps.ClosedOpenInterval(bps.NewFromPercentage(1), bps.NewFromPercentage(1)), true},
+		"a point":            {bps.ClosedInterval(bps.NewFromPercentage(1), bps.NewFromPercentage(1)), false},
+		"no ppb between":     {bps.OpenInterval(bps.NewFromPPB(big.NewInt(1)), bps.NewFromPPB(big.NewInt(2))), true},
+		"one ppb between":    {bps.OpenInterval(bps.NewFromPPB(big.NewInt(1)), bps.NewFromPPB(big.NewInt(3))), false},
+		"unbounded":          {bps.Interval{Upper: bps.NewFromPercentage(1), UpperOpen: true}, false},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.i.IsEmpty(); got != tt.want {
+				t.Errorf("Interval.IsEmpty() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInterval_Intersect(t *testing.T) {
+	tests := map[string]struct {
+		i, i2         string
+		want          string
+		wantEmpty     bool
+		wantUnion     string
+		wantUnionFail bool
+	}{
+		"overlapping": {
+			"[1%, 5%)", "(3%, 10%]",
+			"(3%, 5%)", false,
+			"[1%, 10%]", false,
+		},
+		"nested": {
+			"[1%, 10%]", "(3%, 5%)",
+			"(3%, 5%)", false,
+			"[1%, 10%]", false,
+		},
+		"the same values with different bounds": {
+			"[1%, 5%]", "(1%, 5%)",
+			"(1%, 5%)", false,
+			"[1%, 5%]", false,
+		},
+		"adjacent": {
+			"[1%, 2%)", "[2%, 3%]",
+			"[2%, 2%)", true,
+			"[1%, 3%]", false,
+		},
+		"touching open bounds": {
+			"[1%, 2%)", "(2%, 3%]",
+			"(2%, 2%)", true,
+			"", true,
+		},
+		"disjoint": {
+			"[5%, 6%]", "[1%, 2%]",
+			"[5%, 2%]", true,
+			"", true,
+		},
+		"unbounded": {
+			"(-inf, 5%]", "[1%, +inf)",
+			"[1%, 5%]", false,
+			"(-inf, +inf)", false,
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			i, i2 := mustInterval(tt.i), mustInterval(tt.i2)
+			got := i.Intersect(i2)
+			if got.String() != tt.want {
+				t.Errorf("Interval.Intersect() = %v, want %v", got, tt.want)
+			}
+			if got.IsEmpty() != tt.wantEmpty {
+				t.Errorf("Interval.Intersect().IsEmpty() = %v, want %v", got.IsEmpty(), tt.wantEmpty)
+			}
+			if i.Overlaps(i2) == tt.wantEmpty || i2.Overlaps(i) == tt.wantEmpty {
+				t.Errorf("Interval.Overlaps() = %v, want %v", i.Overlaps(i2), !tt.wantEmpty)
+			}
+			u, ok := i.Union(i2)
+			if ok == tt.wantUnionFail {
+				t.Errorf("Interval.Union() ok = %v, want %v", ok, !tt.wantUnionFail)
+			}
+			if ok && u.String() != tt.wantUnion {
+				t.Errorf("Interval.Union() = %v, want %v", u, tt.wantUnion)
+			}
+			if u2, ok2 := i2.Union(i); ok2 != ok || (ok && u2.String() != u.String()) {
+				t.Errorf("Interval.Union() should be commutative, got %v and %v", u, u2)
+			}
+		})
+	}
+}
+
+func TestInterval_Serialization(t *testing.T) {
+	t.Parallel()
+
+	type Contract struct {
+		Rate bps.Interval `json:"rate"`
+	}
+	c := Contract{Rate: mustInterval("[1.5%, 15%)")}
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"rate":"[1.5%, 15%)"}`; string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+	var got Contract
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, c) {
+		t.Errorf("json.Unmarshal() = %v, want %v", got.Rate, c.Rate)
+	}
+
+	v, _ := c.Rate.Value()
+	var scanned bps.Interval
+	if err := scanned.Scan(v); err != nil || !reflect.DeepEqual(scanned, c.Rate) {
+		t.Errorf("Interval.Scan() = %v, %v, want %v", scanned, err, c.Rate)
+	}
+	if err := scanned.Scan([]byte("(1%, 2%]")); err != nil || scanned.String() != "(1%, 2%]" {
+		t.Errorf("Interval.Scan() = %v, %v", scanned, err)
+	}
+	if err := scanned.Scan(1); err == nil {
+		t.Error("Interval.Scan() should return an error for int")
+	}
+}
+
+func ExampleInterval() {
+	allowed, _ := bps.ParseInterval("[1.5%, 15%)")
+
+	fmt.Println(allowed.Contains(bps.NewFromPercentage(8)))
+	fmt.Println(allowed.Contains(bps.NewFromPercentage(15)))
+	fmt.Println(allowed.Clamp(bps.NewFromPercentage(1)).UnitString(bps.Percentage))
+
+	promotion := bps.ClosedInterval(bps.NewFromPercentage(0), bps.NewFromPercentage(3))
+	fmt.Println(allowed.Intersect(promotion))
+	// Output:
+	// true
+	// false
+	// 1.5%
+	// [1.5%, 3%]
+}
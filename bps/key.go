@@ -0,0 +1,61 @@
+package bps
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/big"
+)
+
+// Key is a comparable representation of BPS, which can be used as a map key.
+// Two keys are equal iff the values of BPS are equal, regardless of how they were made.
+// The zero value of Key means 0.
+type Key struct {
+	// ppb is the value in ppb. It's used when big is empty.
+	ppb int64
+	// big is the decimal value in ppb when it can't be represented as int64.
+	big string
+}
+
+// Key returns the comparable representation of `b`.
+func (b *BPS) Key() Key {
+	s := nilSafe(b)
+	if s.value != nil {
+		return Key{big: s.value.String()}
+	}
+	return Key{ppb: s.ppb}
+}
+
+// BPS returns a new BPS from `k`.
+func (k Key) BPS() *BPS {
+	if k.big == "" {
+		return &BPS{ppb: k.ppb}
+	}
+	v, _ := new(big.Int).SetString(k.big, 10)
+	return newBPS(v)
+}
+
+// String returns the canonical string representation of `k`.
+func (k Key) String() string {
+	return k.BPS().CanonicalString()
+}
+
+// CanonicalString returns the canonical string representation of `b`, which is the shortest exact decimal amount like "0.02645".
+// Unlike String, it doesn't depend on BaseUnit, so it's stable across applications and suitable for signed payloads and idempotency keys.
+func (b *BPS) CanonicalString() string {
+	var buf [32]byte
+	text, _ := b.AppendText(buf[:0])
+	return string(text)
+}
+
+// Hash writes the canonical string representation of `b` to h, prefixed by its length in bytes as a uvarint
+// of encoding/binary, e.g. "\x040.15" for 15%.
+// Equal values always produce the same bytes, so it can be included in a digest deterministically.
+// The prefix frames the value, so that consecutive values like 0.1 and 2 don't produce the same bytes as 0.12.
+func (b *BPS) Hash(h hash.Hash) {
+	var buf [binary.MaxVarintLen64 + 32]byte
+	text, _ := b.AppendText(buf[binary.MaxVarintLen64:binary.MaxVarintLen64])
+	n := binary.PutUvarint(buf[:], uint64(len(text)))
+	// hash.Hash never returns an error
+	_, _ = h.Write(buf[:n])
+	_, _ = h.Write(text)
+}
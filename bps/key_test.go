@@ -0,0 +1,168 @@
+package bps_test
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"go.mercari.io/go-bps/bps"
+)
+
+func TestBPS_CanonicalString(t *testing.T) {
+	huge, _ := new(big.Int).SetString("-123456789012345678901234567890", 10)
+	tests := map[string]struct {
+		b    *bps.BPS
+		want string
+	}{
+		"2.645%": {
+			bps.NewFromDeciBasisPoint(2645),
+			"0.02645",
+		},
+		"15 amounts": {
+			bps.NewFromAmount(15),
+			"15",
+		},
+		"-1 ppb": {
+			bps.NewFromPPB(big.NewInt(-1)),
+			"-0.000000001",
+		},
+		"beyond int64": {
+			bps.NewFromPPB(huge),
+			"-123456789012345678901.23456789",
+		},
+		"nil": {
+			nil,
+			"0",
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.b.CanonicalString(); got != tt.want {
+				t.Errorf("BPS.CanonicalString() = %v, want %v", got, tt.want)
+			}
+			if got := tt.b.Key().String(); got != tt.want {
+				t.Errorf("Key.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBPS_CanonicalString_BaseUnit(t *testing.T) {
+	// backup
+	u := bps.BaseUnit
+
+	b := bps.NewFromPercentage(15)
+	want := b.CanonicalString()
+	bps.BaseUnit = bps.PPB
+	got := b.CanonicalString()
+
+	// teardown
+	bps.BaseUnit = u
+
+	if got != want {
+		t.Errorf("BPS.CanonicalString() = %v, want %v regardless of BaseUnit", got, want)
+	}
+}
+
+func TestBPS_Key(t *testing.T) {
+	t.Parallel()
+
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	values := []*bps.BPS{
+		bps.NewFromPercentage(15),
+		bps.NewFromBasisPoint(1500),
+		bps.MustFromString("0.15"),
+		bps.NewFromAmount(0),
+		nil,
+		&bps.BPS{},
+		bps.NewFromPPB(huge),
+		bps.NewFromPPB(huge).Add(bps.NewFromAmount(1)).Sub(bps.NewFromAmount(1)),
+		bps.NewFromAmount(1),
+	}
+	set := map[bps.Key]int{}
+	for _, v := range values {
+		set[v.Key()]++
+	}
+
+	want := map[bps.Key]int{
+		bps.NewFromPercentage(15).Key(): 3,
+		bps.NewFromAmount(0).Key():      3,
+		bps.NewFromPPB(huge).Key():      2,
+		bps.NewFromAmount(1).Key():      1,
+	}
+	if len(set) != len(want) {
+		t.Errorf("len(set) = %v, want %v", len(set), len(want))
+	}
+	for k, n := range want {
+		if set[k] != n {
+			t.Errorf("set[%v] = %v, want %v", k, set[k], n)
+		}
+	}
+
+	for _, v := range values {
+		if got := v.Key().BPS(); !got.Equal(v) {
+			t.Errorf("Key.BPS() = %v, want %v", got.PPBs(), v.PPBs())
+		}
+	}
+	if (bps.Key{}) != bps.NewFromAmount(0).Key() {
+		t.Error("the zero value of Key should mean 0")
+	}
+}
+
+func TestBPS_Hash(t *testing.T) {
+	t.Parallel()
+
+	digest := func(b *bps.BPS) string {
+		h := sha256.New()
+		b.Hash(h)
+		return fmt.Sprintf("%x", h.Sum(nil))
+	}
+
+	a := digest(bps.NewFromPercentage(15))
+	if b := digest(bps.MustFromString("0.150")); a != b {
+		t.Errorf("the same values should have the same hash, got %v and %v", a, b)
+	}
+	if b := digest(bps.NewFromPercentage(16)); a == b {
+		t.Errorf("the different values should have different hashes, got %v", a)
+	}
+	if want := fmt.Sprintf("%x", sha256.Sum256([]byte("\x040.15"))); a != want {
+		t.Errorf("BPS.Hash() should write the length and the canonical string, got %v, want %v", a, want)
+	}
+
+	// the values written consecutively don't collide
+	digests := func(bs ...*bps.BPS) string {
+		h := sha256.New()
+		for _, b := range bs {
+			b.Hash(h)
+		}
+		return fmt.Sprintf("%x", h.Sum(nil))
+	}
+	if x, y := digests(bps.MustFromString("0.1"), bps.NewFromAmount(2)), digests(bps.MustFromString("0.12")); x == y {
+		t.Errorf("Hash(0.1) + Hash(2) should differ from Hash(0.12), got %v", x)
+	}
+	if x, y := digests(bps.NewFromAmount(1), bps.NewFromAmount(23)), digests(bps.NewFromAmount(12), bps.NewFromAmount(3)); x == y {
+		t.Errorf("Hash(1) + Hash(23) should differ from Hash(12) + Hash(3), got %v", x)
+	}
+
+	// a value beyond int64 is longer than the inline buffer
+	huge := bps.NewFromPPB(new(big.Int).Lsh(big.NewInt(1), 200))
+	text := huge.CanonicalString()
+	if want := fmt.Sprintf("%x", sha256.Sum256([]byte(string(rune(len(text)))+text))); digest(huge) != want {
+		t.Errorf("BPS.Hash(%s) = %v, want %v", text, digest(huge), want)
+	}
+}
+
+func ExampleBPS_Key() {
+	rates := map[bps.Key]string{}
+	rates[bps.NewFromPercentage(15).Key()] = "standard"
+	rates[bps.NewFromDeciBasisPoint(2645).Key()] = "reduced"
+
+	fmt.Println(rates[bps.MustFromString("0.15").Key()])
+	fmt.Println(bps.NewFromBasisPoint(1500).CanonicalString())
+	// Output:
+	// standard
+	// 0.15
+}
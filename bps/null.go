@@ -0,0 +1,42 @@
+package bps
+
+import (
+	"database/sql"
+	"database/sql/driver"
+)
+
+// make sure that the *NullBPS implements some interfaces.
+var _ interface {
+	sql.Scanner
+	driver.Valuer
+} = (*NullBPS)(nil)
+
+// NullBPS represents a BPS that may be null like sql.NullString.
+// It implements the sql.Scanner interface so it can be used as a scan destination of a nullable column.
+type NullBPS struct {
+	BPS   BPS
+	Valid bool // Valid is true if BPS is not NULL
+}
+
+// Scan implements the sql.Scanner interface for database deserialization.
+func (n *NullBPS) Scan(value interface{}) error {
+	if value == nil {
+		n.BPS, n.Valid = BPS{}, false
+		return nil
+	}
+	if err := n.BPS.Scan(value); err != nil {
+		n.Valid = false
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// Value implements the driver.Valuer interface for database serialization.
+// It returns nil if `n` is not valid.
+func (n NullBPS) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.BPS.Value()
+}
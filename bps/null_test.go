@@ -0,0 +1,79 @@
+package bps_test
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"testing"
+
+	"go.mercari.io/go-bps/bps"
+)
+
+func TestNullBPS_Scan(t *testing.T) {
+	tests := map[string]struct {
+		value   interface{}
+		want    bps.NullBPS
+		wantErr bool
+	}{
+		"nil is not valid": {
+			nil,
+			bps.NullBPS{},
+			false,
+		},
+		"int64": {
+			int64(15),
+			bps.NullBPS{BPS: *bps.NewFromBaseUnit(15), Valid: true},
+			false,
+		},
+		"string": {
+			"0.02645",
+			bps.NullBPS{BPS: *bps.NewFromDeciBasisPoint(2645), Valid: true},
+			false,
+		},
+		"If invalid type, it should return an error": {
+			1.5,
+			bps.NullBPS{},
+			true,
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got := bps.NullBPS{BPS: *bps.NewFromAmount(1), Valid: true}
+			err := got.Scan(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NullBPS.Scan() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NullBPS.Scan() = %v, want %v", got, tt.want)
+			}
+			if tt.wantErr && got.Valid {
+				t.Errorf("NullBPS.Scan() should not be valid after an error")
+			}
+		})
+	}
+}
+
+func TestNullBPS_Value(t *testing.T) {
+	tests := map[string]struct {
+		n    bps.NullBPS
+		want driver.Value
+	}{
+		"not valid": {bps.NullBPS{BPS: *bps.NewFromAmount(1)}, nil},
+		"valid":     {bps.NullBPS{BPS: *bps.NewFromBaseUnit(15), Valid: true}, "15"},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tt.n.Value()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("NullBPS.Value() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
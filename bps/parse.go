@@ -0,0 +1,118 @@
+package bps
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// ppbDigits is the number of fractional digits of an amount which can be represented in ppb.
+const ppbDigits = 9
+
+// ParseBytes returns a new BPS from a byte slice representation of an amount, e.g. "0.15" means 15%.
+// It accepts the same format as NewFromString: an optional sign, digits and an optional decimal point.
+// Digits beyond ppb are rounded down like NewFromString.
+//
+// ParseBytes scans the bytes by hand, so it doesn't allocate except the result as long as the value fits in int64 ppbs.
+func ParseBytes(text []byte) (*BPS, error) {
+	b := new(BPS)
+	if err := b.parse(text); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// parse sets `b` to the value of text.
+// `b` is not modified if text is invalid.
+func (b *BPS) parse(text []byte) error {
+	s := text
+	neg := false
+	if len(s) > 0 && (s[0] == '+' || s[0] == '-') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+
+	var (
+		acc      decimalAccumulator
+		digits   int
+		frac     = -1 // the number of fractional digits, -1 until a decimal point appears
+		truncate bool // whether non-zero digits beyond ppb are dropped
+	)
+	for _, c := range s {
+		switch {
+		case c == '.':
+			if frac >= 0 {
+				return fmt.Errorf("can't convert %s to BPS: too many .s", text)
+			}
+			frac = 0
+		case '0' <= c && c <= '9':
+			digits++
+			if frac >= 0 {
+				if frac == ppbDigits {
+					truncate = truncate || c != '0'
+					continue
+				}
+				frac++
+			}
+			acc.mulAdd(10, uint64(c-'0'))
+		default:
+			return fmt.Errorf("can't convert %s to BPS", text)
+		}
+	}
+	if digits == 0 {
+		return fmt.Errorf("can't convert %s to BPS", text)
+	}
+
+	if frac < 0 {
+		frac = 0
+	}
+	for ; frac < ppbDigits; frac++ {
+		acc.mulAdd(10, 0)
+	}
+	// round toward negative infinity like big.Int.Div which NewFromString used to rely on.
+	if neg && truncate {
+		acc.mulAdd(1, 1)
+	}
+	acc.setTo(b, neg)
+	return nil
+}
+
+// decimalAccumulator accumulates a non-negative integer digit by digit.
+// It works on uint64 and spills to *big.Int once the value overflows.
+type decimalAccumulator struct {
+	mag uint64
+	big *big.Int
+}
+
+// mulAdd sets the accumulated value to value * m + d.
+func (a *decimalAccumulator) mulAdd(m, d uint64) {
+	if a.big == nil {
+		if a.mag <= (math.MaxUint64-d)/m {
+			a.mag = a.mag*m + d
+			return
+		}
+		a.big = new(big.Int).SetUint64(a.mag)
+	}
+	a.big.Mul(a.big, new(big.Int).SetUint64(m))
+	a.big.Add(a.big, new(big.Int).SetUint64(d))
+}
+
+// setTo sets `b` to the accumulated value as ppb, negated if neg is true.
+func (a *decimalAccumulator) setTo(b *BPS, neg bool) {
+	if a.big == nil {
+		switch {
+		case !neg && a.mag <= math.MaxInt64:
+			b.setInt64(int64(a.mag))
+			return
+		case neg && a.mag <= 1<<63:
+			// -int64(1<<63) is math.MinInt64 as expected, thanks to the two's complement.
+			b.setInt64(-int64(a.mag))
+			return
+		}
+		a.big = new(big.Int).SetUint64(a.mag)
+	}
+	if neg {
+		a.big.Neg(a.big)
+	}
+	b.setBig(a.big)
+}
@@ -0,0 +1,290 @@
+package bps_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"go.mercari.io/go-bps/bps"
+)
+
+func TestParseBytes(t *testing.T) {
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890000000000", 10)
+	tests := map[string]struct {
+		arg     string
+		want    *bps.BPS
+		wantErr bool
+	}{
+		"int part and decimal part": {
+			"123.456",
+			bps.NewFromBasisPoint(1234560),
+			false,
+		},
+		"only int part": {
+			"123",
+			bps.NewFromBasisPoint(1230000),
+			false,
+		},
+		"only decimal part": {
+			".1234",
+			bps.NewFromBasisPoint(1234),
+			false,
+		},
+		"trailing decimal point": {
+			"15.",
+			bps.NewFromAmount(15),
+			false,
+		},
+		"positive sign": {
+			"+0.15",
+			bps.NewFromPercentage(15),
+			false,
+		},
+		"negative value": {
+			"-123.456",
+			bps.NewFromBasisPoint(-1234560),
+			false,
+		},
+		"negative zero": {
+			"-.0",
+			bps.NewFromAmount(0),
+			false,
+		},
+		"digits beyond ppb are rounded down": {
+			"0.0000000019",
+			bps.NewFromPPB(big.NewInt(1)),
+			false,
+		},
+		"negative digits beyond ppb are rounded down to negative infinity": {
+			"-0.0000000011",
+			bps.NewFromPPB(big.NewInt(-2)),
+			false,
+		},
+		"more than 18 decimals": {
+			"0.1234567890123456789012",
+			bps.NewFromPPB(big.NewInt(123456789)),
+			false,
+		},
+		"zeros beyond ppb are ignored": {
+			"-0.000000001000000000000",
+			bps.NewFromPPB(big.NewInt(-1)),
+			false,
+		},
+		"max int64 ppbs": {
+			"9223372036.854775807",
+			bps.NewFromPPB(big.NewInt(math.MaxInt64)),
+			false,
+		},
+		"min int64 ppbs": {
+			"-9223372036.854775808",
+			bps.NewFromPPB(big.NewInt(math.MinInt64)),
+			false,
+		},
+		"beyond int64 ppbs": {
+			"123456789012345678901234567890",
+			bps.NewFromPPB(huge),
+			false,
+		},
+		"negative beyond int64 ppbs": {
+			"-123456789012345678901234567890",
+			bps.NewFromPPB(new(big.Int).Neg(huge)),
+			false,
+		},
+		"If include multi dots, it should return an error": {
+			"123.45.6",
+			nil,
+			true,
+		},
+		"If only a decimal point, it should return an error": {
+			".",
+			nil,
+			true,
+		},
+		"If only a sign, it should return an error": {
+			"-",
+			nil,
+			true,
+		},
+		"If empty, it should return an error": {
+			"",
+			nil,
+			true,
+		},
+		"If include spaces, it should return an error": {
+			" 1",
+			nil,
+			true,
+		},
+		"If exponent format, it should return an error": {
+			"1e5",
+			nil,
+			true,
+		},
+		"If base 16 format, it should return an error": {
+			"0xF5",
+			nil,
+			true,
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := bps.ParseBytes([]byte(tt.arg))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseBytes() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseBytes() = %v, want %v", got.PPBs(), tt.want.PPBs())
+			}
+		})
+	}
+}
+
+// legacyNewFromString is the former implementation of NewFromString, which is used as the reference of ParseBytes.
+// It's correct only for up to 18 decimals since math.Pow10 overflows int64 beyond that.
+func legacyNewFromString(value string) (*bps.BPS, error) {
+	var intString string
+	var mul int64 = 1
+
+	parts := strings.Split(value, ".")
+	if len(parts) == 1 {
+		intString = value
+	} else if len(parts) == 2 {
+		decimalPart := strings.TrimRight(parts[1], "0")
+		intString = parts[0] + decimalPart
+		if intString == "" && parts[1] != "" {
+			intString = "0"
+		}
+		expInt := len(decimalPart)
+		mul = int64(math.Pow10(expInt))
+	} else {
+		return nil, fmt.Errorf("can't convert %s to BPS: too many .s", value)
+	}
+
+	parsed, ok := new(big.Int).SetString(intString, 10)
+	if !ok {
+		return nil, fmt.Errorf("can't convert %s to BPS", value)
+	}
+
+	return bps.NewFromPPB(parsed).Mul(bps.DenomAmount).Div(mul), nil
+}
+
+// randomDecimal returns a random string which looks like a decimal, or sometimes is broken.
+func randomDecimal(r *rand.Rand) string {
+	const chars = "0123456789"
+	var sb strings.Builder
+	switch r.Intn(4) {
+	case 0:
+		sb.WriteByte('-')
+	case 1:
+		sb.WriteByte('+')
+	}
+	for i := r.Intn(22); i > 0; i-- {
+		sb.WriteByte(chars[r.Intn(len(chars))])
+	}
+	if r.Intn(4) > 0 {
+		sb.WriteByte('.')
+		for i := r.Intn(19); i > 0; i-- {
+			sb.WriteByte(chars[r.Intn(len(chars))])
+		}
+	}
+	// break the string sometimes
+	if r.Intn(10) == 0 {
+		s := []byte(sb.String())
+		junk := ".-+e x"
+		s = append(s, 0)
+		i := r.Intn(len(s))
+		copy(s[i+1:], s[i:])
+		s[i] = junk[r.Intn(len(junk))]
+		return string(s)
+	}
+	return sb.String()
+}
+
+func TestParseBytes_CompareWithLegacy(t *testing.T) {
+	t.Parallel()
+
+	// the legacy implementation also accepted a sign after the decimal point like ".-5" by concatenating the parts.
+	valid := regexp.MustCompile(`^[+-]?[0-9]*(\.[0-9]*)?$`)
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100000; i++ {
+		s := randomDecimal(r)
+		got, err := bps.ParseBytes([]byte(s))
+		if !valid.MatchString(s) || strings.Trim(s, "+-.") == "" {
+			if err == nil {
+				t.Errorf("ParseBytes(%q) = %v, want an error", s, got.PPBs())
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseBytes(%q) error = %v", s, err)
+			continue
+		}
+
+		if i := strings.Index(s, "."); i >= 0 && len(strings.TrimRight(s[i+1:], "0")) > 18 {
+			// the legacy implementation overflows
+			continue
+		}
+		want, wantErr := legacyNewFromString(s)
+		if wantErr != nil {
+			// the legacy implementation rejected a sign without int part and non-zero decimals like "-.0"
+			if !got.IsZero() {
+				t.Errorf("ParseBytes(%q) = %v, want an error %v", s, got.PPBs(), wantErr)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ParseBytes(%q) = %v, want %v", s, got.PPBs(), want.PPBs())
+		}
+	}
+}
+
+func TestParseBytes_Allocs(t *testing.T) {
+	text := []byte("-0.02645")
+	b := new(bps.BPS)
+	allocs := testing.AllocsPerRun(100, func() {
+		if err := b.UnmarshalText(text); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("BPS.UnmarshalText() allocates %v times, want 0", allocs)
+	}
+}
+
+func ExampleParseBytes() {
+	b, _ := bps.ParseBytes([]byte("0.02645"))
+	fmt.Println(b.DeciBasisPoints())
+	// digits beyond ppb are rounded down
+	b, _ = bps.ParseBytes([]byte("0.12345678901234567890"))
+	fmt.Println(b.PPBs())
+	// Output:
+	// 2645
+	// 123456789
+}
+
+func BenchmarkParseBytes(b *testing.B) {
+	text := []byte("0.02645")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := bps.ParseBytes(text); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseBytes_Legacy(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := legacyNewFromString("0.02645"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package bps
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// make sure that Rate implements some interfaces.
+var _ interface {
+	fmt.Stringer
+	driver.Valuer
+	encoding.TextMarshaler
+} = Rate{}
+
+var _ interface {
+	sql.Scanner
+	encoding.TextUnmarshaler
+} = (*Rate)(nil)
+
+// Rate is a ratio like an interest rate or a fee rate, e.g. 2.645%.
+// Unlike BPS, it provides only the operations which are meaningful for a rate, so it can't be mixed up with Amount.
+// The zero value of Rate is 0%.
+type Rate struct {
+	v BPS
+}
+
+// RateFromBPS returns a new Rate from `b`. It's useful to build a Rate by the BPS constructors:
+//
+//	RateFromBPS(NewFromDeciBasisPoint(2645)) // 2.645%
+func RateFromBPS(b *BPS) Rate {
+	var r Rate
+	r.v.Set(b)
+	return r
+}
+
+// BPS returns `r` as a new BPS for backward compatibility.
+func (r Rate) BPS() *BPS {
+	return new(BPS).Set(&r.v)
+}
+
+// Add returns r + r2.
+func (r Rate) Add(r2 Rate) Rate {
+	r.v.SetAdd(&r.v, &r2.v)
+	return r
+}
+
+// Sub returns r - r2.
+func (r Rate) Sub(r2 Rate) Rate {
+	r.v.SetSub(&r.v, &r2.v)
+	return r
+}
+
+// Neg returns -r.
+func (r Rate) Neg() Rate {
+	r.v.SetNeg(&r.v)
+	return r
+}
+
+// Mul returns the amount of `a` at the rate `r`, rounded down to ppb like BPS.Div.
+// The result is exact when `a` is an integer amount like a principal. Use MulRound to get an integer amount.
+func (r Rate) Mul(a Amount) Amount {
+	var res Amount
+	if a.v.value == nil && a.v.ppb%DenomAmount == 0 {
+		res.v.SetMul(&r.v, a.v.ppb/DenomAmount)
+		return res
+	}
+	p := new(big.Int).Mul(r.v.bigValue(), a.v.bigValue())
+	res.v.setBig(p.Div(p, big.NewInt(DenomAmount)))
+	return res
+}
+
+// MulRound returns the amount of `a` at the rate `r` rounded to an integer amount by `mode`.
+// Unlike Mul followed by Amount.Round, it rounds the exact product only once,
+// e.g. 1 ppb of 0.5 rounded up is 1 while Mul rounds it down to 0 first.
+func (r Rate) MulRound(a Amount, mode RoundingMode) Amount {
+	var res Amount
+	res.v.setProduct(&r.v, &a.v, mode)
+	return res
+}
+
+// Cmp compares `r` and r2 and returns -1, 0 or +1.
+func (r Rate) Cmp(r2 Rate) int {
+	return r.v.Cmp(&r2.v)
+}
+
+// Equal reports whether r == r2.
+func (r Rate) Equal(r2 Rate) bool {
+	return r.v.Equal(&r2.v)
+}
+
+// IsZero reports whether `r` is 0%.
+func (r Rate) IsZero() bool {
+	return r.v.IsZero()
+}
+
+// Sign returns -1 if r < 0, 0 if r == 0 and +1 if r > 0.
+func (r Rate) Sign() int {
+	return r.v.Sign()
+}
+
+// String returns the canonical string representation of `r` like "0.02645".
+func (r Rate) String() string {
+	return r.v.CanonicalString()
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// It uses the canonical string representation.
+func (r Rate) MarshalText() ([]byte, error) {
+	return r.v.AppendText(nil)
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (r *Rate) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return errors.New("Rate.UnmarshalText: no data")
+	}
+	return r.v.parse(text)
+}
+
+// Value implements the driver.Valuer interface for database serialization.
+// It uses the canonical string representation like "0.02645", which Scan can parse,
+// so the column must be a decimal type with 9 fractional digits like DECIMAL(20, 9) or a string type.
+//
+// It's not compatible with an integer column of BaseUnit which BPS.Value writes, since such a column can't hold "0.02645".
+// Scan reads the integer column, so write r.BPS().Value() instead to keep using it.
+func (r Rate) Value() (driver.Value, error) {
+	return r.v.CanonicalString(), nil
+}
+
+// Scan implements the sql.Scanner interface for database deserialization like BPS.Scan.
+// It also accepts []byte as the string representation.
+// An integer is read as a count of BaseUnit to read the integer columns of BPS, see Value for the column types.
+func (r *Rate) Scan(value interface{}) error {
+	if r == nil {
+		return errors.New("Rate.Scan: nil receiver")
+	}
+	if v, ok := value.([]byte); ok {
+		return r.UnmarshalText(v)
+	}
+	return r.v.Scan(value)
+}
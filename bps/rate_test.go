@@ -0,0 +1,241 @@
+package bps_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"testing"
+
+	"go.mercari.io/go-bps/bps"
+)
+
+func TestRate_Mul(t *testing.T) {
+	tests := map[string]struct {
+		r    bps.Rate
+		a    bps.Amount
+		want bps.Amount
+	}{
+		"8% of 14999 = 1199.92": {
+			bps.RateFromBPS(bps.NewFromPercentage(8)),
+			bps.NewAmount(14999),
+			bps.AmountFromBPS(bps.MustFromString("1199.92")),
+		},
+		"2.645% of 14999 = 396.72355": {
+			bps.RateFromBPS(bps.NewFromDeciBasisPoint(2645)),
+			bps.NewAmount(14999),
+			bps.AmountFromBPS(bps.MustFromString("396.72355")),
+		},
+		"2.645% of 0.5 = 0.013225": {
+			bps.RateFromBPS(bps.NewFromDeciBasisPoint(2645)),
+			bps.AmountFromBPS(bps.MustFromString("0.5")),
+			bps.AmountFromBPS(bps.MustFromString("0.013225")),
+		},
+		"0.000000001 of 0.5 is rounded down to zero": {
+			bps.RateFromBPS(bps.NewFromPPB(big.NewInt(1))),
+			bps.AmountFromBPS(bps.MustFromString("0.5")),
+			bps.Amount{},
+		},
+		"-0.000000001 of 0.5 is rounded down to -1 ppb": {
+			bps.RateFromBPS(bps.NewFromPPB(big.NewInt(-1))),
+			bps.AmountFromBPS(bps.MustFromString("0.5")),
+			bps.AmountFromBPS(bps.MustFromString("-0.000000001")),
+		},
+		"zero rate": {
+			bps.Rate{},
+			bps.NewAmount(14999),
+			bps.Amount{},
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.r.Mul(tt.a); !got.Equal(tt.want) {
+				t.Errorf("Rate.Mul() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRate_MulRound(t *testing.T) {
+	half := bps.AmountFromBPS(bps.MustFromString("0.5"))
+	tests := map[string]struct {
+		r    bps.Rate
+		a    bps.Amount
+		mode bps.RoundingMode
+		want bps.Amount
+	}{
+		"2.645% of 14999 rounded half up": {
+			bps.RateFromBPS(bps.NewFromDeciBasisPoint(2645)), bps.NewAmount(14999), bps.RoundHalfUp, bps.NewAmount(397),
+		},
+		"1 ppb of 0.5 rounded up": {
+			bps.RateFromBPS(bps.NewFromPPB(big.NewInt(1))), half, bps.RoundUp, bps.NewAmount(1),
+		},
+		"1 ppb of 0.5 rounded to ceiling": {
+			bps.RateFromBPS(bps.NewFromPPB(big.NewInt(1))), half, bps.RoundCeiling, bps.NewAmount(1),
+		},
+		"-1 ppb of 0.5 rounded to floor": {
+			bps.RateFromBPS(bps.NewFromPPB(big.NewInt(-1))), half, bps.RoundFloor, bps.NewAmount(-1),
+		},
+		"50% of 1.000000001 rounded half down": {
+			bps.RateFromBPS(bps.NewFromPercentage(50)), bps.AmountFromBPS(bps.MustFromString("1.000000001")), bps.RoundHalfDown, bps.NewAmount(1),
+		},
+		"50% of 1.000000001 rounded half even": {
+			bps.RateFromBPS(bps.NewFromPercentage(50)), bps.AmountFromBPS(bps.MustFromString("1.000000001")), bps.RoundHalfEven, bps.NewAmount(1),
+		},
+		"50% of 5 rounded half even": {
+			bps.RateFromBPS(bps.NewFromPercentage(50)), bps.NewAmount(5), bps.RoundHalfEven, bps.NewAmount(2),
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.r.MulRound(tt.a, tt.mode); !got.Equal(tt.want) {
+				t.Errorf("Rate.MulRound() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRate_Arithmetic(t *testing.T) {
+	t.Parallel()
+
+	r := bps.RateFromBPS(bps.NewFromPercentage(8))
+	r2 := bps.RateFromBPS(bps.NewFromDeciBasisPoint(2645))
+
+	if got, want := r.Add(r2), bps.RateFromBPS(bps.NewFromDeciBasisPoint(10645)); !got.Equal(want) {
+		t.Errorf("Rate.Add() = %v, want %v", got, want)
+	}
+	if got, want := r.Sub(r2), bps.RateFromBPS(bps.NewFromDeciBasisPoint(5355)); !got.Equal(want) {
+		t.Errorf("Rate.Sub() = %v, want %v", got, want)
+	}
+	if got, want := r.Neg().Sign(), -1; got != want {
+		t.Errorf("Rate.Neg().Sign() = %v, want %v", got, want)
+	}
+	if got := r.Cmp(r2); got != 1 {
+		t.Errorf("Rate.Cmp() = %v, want 1", got)
+	}
+	if !(bps.Rate{}).IsZero() {
+		t.Error("the zero value of Rate should be zero")
+	}
+	if got := r.String(); got != "0.08" {
+		t.Errorf("Rate.String() = %v, want 0.08", got)
+	}
+	// the operations never mutate the operands
+	if got, want := r.BPS(), bps.NewFromPercentage(8); !got.Equal(want) {
+		t.Errorf("Rate.BPS() = %v, want %v", got, want)
+	}
+}
+
+func TestRate_Serialization(t *testing.T) {
+	t.Parallel()
+
+	type Fee struct {
+		Rate bps.Rate `json:"rate"`
+	}
+	fee := Fee{Rate: bps.RateFromBPS(bps.NewFromDeciBasisPoint(2645))}
+	data, err := json.Marshal(fee)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"rate":"0.02645"}`; string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+	var got Fee
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !got.Rate.Equal(fee.Rate) {
+		t.Errorf("json.Unmarshal() = %v, want %v", got.Rate, fee.Rate)
+	}
+	if err := json.Unmarshal([]byte(`{"rate":""}`), &got); err == nil {
+		t.Error("json.Unmarshal() should return an error for empty string")
+	}
+
+	v, err := fee.Rate.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var scanned bps.Rate
+	if err := scanned.Scan(v); err != nil {
+		t.Fatal(err)
+	}
+	if !scanned.Equal(fee.Rate) {
+		t.Errorf("Rate.Scan() = %v, want %v", scanned, fee.Rate)
+	}
+	if err := scanned.Scan([]byte("0.08")); err != nil || scanned.String() != "0.08" {
+		t.Errorf("Rate.Scan() = %v, %v, want 0.08", scanned, err)
+	}
+	if err := (*bps.Rate)(nil).Scan("0.08"); err == nil {
+		t.Error("Rate.Scan() should return an error for nil receiver")
+	}
+}
+
+func TestRate_DatabaseColumns(t *testing.T) {
+	rate := bps.RateFromBPS(bps.NewFromDeciBasisPoint(2645))
+	tests := map[string]struct {
+		value func() (interface{}, error)
+		// column converts the written value like a database column does.
+		column func(t *testing.T, v interface{}) interface{}
+	}{
+		"decimal column returns []byte": {
+			func() (interface{}, error) { return rate.Value() },
+			func(t *testing.T, v interface{}) interface{} { return []byte(v.(string) + "0000") },
+		},
+		"string column": {
+			func() (interface{}, error) { return rate.Value() },
+			func(t *testing.T, v interface{}) interface{} { return v },
+		},
+		"integer column of BPS needs BPS.Value": {
+			func() (interface{}, error) { return rate.BPS().Value() },
+			func(t *testing.T, v interface{}) interface{} {
+				n, err := strconv.ParseInt(v.(string), 10, 64)
+				if err != nil {
+					t.Fatalf("BPS.Value() = %v, not an integer: %v", v, err)
+				}
+				return n
+			},
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			v, err := tt.value()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got bps.Rate
+			if err := got.Scan(tt.column(t, v)); err != nil {
+				t.Fatalf("Rate.Scan() error = %v", err)
+			}
+			if !got.Equal(rate) {
+				t.Errorf("Rate.Scan() = %v, want %v", got, rate)
+			}
+		})
+	}
+
+	// Rate.Value can't be stored in an integer column
+	v, _ := rate.Value()
+	if _, err := strconv.ParseInt(v.(string), 10, 64); err == nil {
+		t.Errorf("Rate.Value() = %v, want a decimal", v)
+	}
+}
+
+func ExampleRate() {
+	rate := bps.RateFromBPS(bps.NewFromDeciBasisPoint(2645))
+	principal := bps.NewAmount(14999)
+
+	fee := rate.Mul(principal)
+	fmt.Println(fee)
+	fmt.Println(fee.Int64(bps.RoundHalfUp))
+
+	effective, _ := fee.Round(bps.RoundDown).Div(principal, bps.RoundHalfUp)
+	fmt.Println(effective)
+	// Output:
+	// 396.72355
+	// 397 <nil>
+	// 0.02640176
+}
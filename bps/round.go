@@ -0,0 +1,125 @@
+package bps
+
+import "math/big"
+
+// RoundingMode determines how a value is rounded when it doesn't fit on a unit grid.
+type RoundingMode int
+
+// List of values that `RoundingMode` can take.
+const (
+	// RoundDown rounds toward zero, that means truncation.
+	RoundDown RoundingMode = iota
+	// RoundUp rounds away from zero.
+	RoundUp
+	// RoundFloor rounds toward negative infinity.
+	RoundFloor
+	// RoundCeiling rounds toward positive infinity.
+	RoundCeiling
+	// RoundHalfUp rounds to the nearest neighbor, ties away from zero.
+	RoundHalfUp
+	// RoundHalfDown rounds to the nearest neighbor, ties toward zero.
+	RoundHalfDown
+	// RoundHalfEven rounds to the nearest neighbor, ties to the even neighbor (banker's rounding).
+	RoundHalfEven
+)
+
+var one = big.NewInt(1)
+
+// RoundTo returns `b` rounded to a multiple of `u` by `mode`.
+// e.g. RoundTo(HalfBasisPoint, RoundHalfUp) snaps `b` to the nearest half basis point.
+func (b *BPS) RoundTo(u Unit, mode RoundingMode) *BPS {
+	d := big.NewInt(u.denom())
+	q := quoRound(nilSafe(b).bigValue(), d, mode)
+	return newBPS(q.Mul(q, d))
+}
+
+// TruncateTo returns `b` truncated toward zero to a multiple of `u`.
+func (b *BPS) TruncateTo(u Unit) *BPS {
+	return b.RoundTo(u, RoundDown)
+}
+
+// IsMultipleOf reports whether `b` is on the grid of `u`, that means `b` can be represented as an integer count of `u`.
+func (b *BPS) IsMultipleOf(u Unit) bool {
+	s := nilSafe(b)
+	if s.value == nil {
+		return s.ppb%u.denom() == 0
+	}
+	r := new(big.Int).Rem(s.value, big.NewInt(u.denom()))
+	return r.Sign() == 0
+}
+
+// quoRound returns n / d rounded to an integer by `mode`.
+// It panics if d is zero like big.Int.
+func quoRound(n, d *big.Int, mode RoundingMode) *big.Int {
+	q, r := new(big.Int).QuoRem(n, d, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+
+	// q is truncated toward zero, so decide whether it should move one step away from zero.
+	neg := (n.Sign() < 0) != (d.Sign() < 0)
+	var away bool
+	switch mode {
+	case RoundUp:
+		away = true
+	case RoundFloor:
+		away = neg
+	case RoundCeiling:
+		away = !neg
+	case RoundHalfUp, RoundHalfDown, RoundHalfEven:
+		half := new(big.Int).Lsh(r.Abs(r), 1).CmpAbs(d)
+		switch {
+		case half > 0:
+			away = true
+		case half == 0:
+			away = mode == RoundHalfUp || (mode == RoundHalfEven && q.Bit(0) == 1)
+		}
+	}
+
+	if away {
+		if neg {
+			return q.Sub(q, one)
+		}
+		return q.Add(q, one)
+	}
+	return q
+}
+
+// quoRound64 returns n / d rounded to an integer by `mode` like quoRound without allocations.
+// d must be positive.
+func quoRound64(n, d int64, mode RoundingMode) int64 {
+	q, r := n/d, n%d
+	if r == 0 {
+		return q
+	}
+
+	neg := n < 0
+	if r < 0 {
+		r = -r
+	}
+	var away bool
+	switch mode {
+	case RoundUp:
+		away = true
+	case RoundFloor:
+		away = neg
+	case RoundCeiling:
+		away = !neg
+	case RoundHalfUp, RoundHalfDown, RoundHalfEven:
+		// r < d, so d - r doesn't overflow unlike 2 * r.
+		switch {
+		case r > d-r:
+			away = true
+		case r == d-r:
+			away = mode == RoundHalfUp || (mode == RoundHalfEven && q%2 != 0)
+		}
+	}
+
+	if away {
+		if neg {
+			return q - 1
+		}
+		return q + 1
+	}
+	return q
+}
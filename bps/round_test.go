@@ -0,0 +1,219 @@
+package bps_test
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"go.mercari.io/go-bps/bps"
+)
+
+func TestBPS_RoundTo(t *testing.T) {
+	tests := map[string]struct {
+		b    *bps.BPS
+		unit bps.Unit
+		mode bps.RoundingMode
+		want *bps.BPS
+	}{
+		"2.3 half basis points rounded down = 2 half basis points": {
+			bps.NewFromPPB(big.NewInt(115000)),
+			bps.HalfBasisPoint,
+			bps.RoundDown,
+			bps.NewFromHalfBasisPoint(2),
+		},
+		"2.3 half basis points rounded up = 3 half basis points": {
+			bps.NewFromPPB(big.NewInt(115000)),
+			bps.HalfBasisPoint,
+			bps.RoundUp,
+			bps.NewFromHalfBasisPoint(3),
+		},
+		"-2.3 half basis points rounded down = -2 half basis points": {
+			bps.NewFromPPB(big.NewInt(-115000)),
+			bps.HalfBasisPoint,
+			bps.RoundDown,
+			bps.NewFromHalfBasisPoint(-2),
+		},
+		"-2.3 half basis points rounded up = -3 half basis points": {
+			bps.NewFromPPB(big.NewInt(-115000)),
+			bps.HalfBasisPoint,
+			bps.RoundUp,
+			bps.NewFromHalfBasisPoint(-3),
+		},
+		"-2.3 half basis points rounded to floor = -3 half basis points": {
+			bps.NewFromPPB(big.NewInt(-115000)),
+			bps.HalfBasisPoint,
+			bps.RoundFloor,
+			bps.NewFromHalfBasisPoint(-3),
+		},
+		"-2.3 half basis points rounded to ceiling = -2 half basis points": {
+			bps.NewFromPPB(big.NewInt(-115000)),
+			bps.HalfBasisPoint,
+			bps.RoundCeiling,
+			bps.NewFromHalfBasisPoint(-2),
+		},
+		"2.5 half basis points rounded half up = 3 half basis points": {
+			bps.NewFromPPB(big.NewInt(125000)),
+			bps.HalfBasisPoint,
+			bps.RoundHalfUp,
+			bps.NewFromHalfBasisPoint(3),
+		},
+		"-2.5 half basis points rounded half up = -3 half basis points": {
+			bps.NewFromPPB(big.NewInt(-125000)),
+			bps.HalfBasisPoint,
+			bps.RoundHalfUp,
+			bps.NewFromHalfBasisPoint(-3),
+		},
+		"2.5 half basis points rounded half down = 2 half basis points": {
+			bps.NewFromPPB(big.NewInt(125000)),
+			bps.HalfBasisPoint,
+			bps.RoundHalfDown,
+			bps.NewFromHalfBasisPoint(2),
+		},
+		"2.5 half basis points rounded half even = 2 half basis points": {
+			bps.NewFromPPB(big.NewInt(125000)),
+			bps.HalfBasisPoint,
+			bps.RoundHalfEven,
+			bps.NewFromHalfBasisPoint(2),
+		},
+		"3.5 half basis points rounded half even = 4 half basis points": {
+			bps.NewFromPPB(big.NewInt(175000)),
+			bps.HalfBasisPoint,
+			bps.RoundHalfEven,
+			bps.NewFromHalfBasisPoint(4),
+		},
+		"-3.5 half basis points rounded half even = -4 half basis points": {
+			bps.NewFromPPB(big.NewInt(-175000)),
+			bps.HalfBasisPoint,
+			bps.RoundHalfEven,
+			bps.NewFromHalfBasisPoint(-4),
+		},
+		"2.6 half basis points rounded half down = 3 half basis points": {
+			bps.NewFromPPB(big.NewInt(130000)),
+			bps.HalfBasisPoint,
+			bps.RoundHalfDown,
+			bps.NewFromHalfBasisPoint(3),
+		},
+		"396.72355 amounts rounded half up = 397 amounts": {
+			bps.NewFromDeciBasisPoint(2645).Mul(14999),
+			bps.Unity,
+			bps.RoundHalfUp,
+			bps.NewFromAmount(397),
+		},
+		"a value on the grid is not changed": {
+			bps.NewFromBasisPoint(15),
+			bps.HalfBasisPoint,
+			bps.RoundUp,
+			bps.NewFromBasisPoint(15),
+		},
+		"nil is rounded to zero": {
+			&bps.BPS{},
+			bps.Percentage,
+			bps.RoundCeiling,
+			bps.NewFromAmount(0),
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got := tt.b.RoundTo(tt.unit, tt.mode)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BPS.RoundTo() = %v, want %v", got.PPBs(), tt.want.PPBs())
+			}
+			assertImmutableOperation(t, "BPS.RoundTo()", got, tt.b)
+		})
+	}
+}
+
+func TestBPS_TruncateTo(t *testing.T) {
+	tests := map[string]struct {
+		b    *bps.BPS
+		unit bps.Unit
+		want *bps.BPS
+	}{
+		"1.99 percentages truncated to percentage = 1 percentage": {
+			bps.NewFromBasisPoint(199),
+			bps.Percentage,
+			bps.NewFromPercentage(1),
+		},
+		"-1.99 percentages truncated to percentage = -1 percentage": {
+			bps.NewFromBasisPoint(-199),
+			bps.Percentage,
+			bps.NewFromPercentage(-1),
+		},
+		"123 ppbs truncated to ppm = 0": {
+			bps.NewFromPPB(big.NewInt(123)),
+			bps.PPM,
+			bps.NewFromAmount(0),
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got := tt.b.TruncateTo(tt.unit)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BPS.TruncateTo() = %v, want %v", got.PPBs(), tt.want.PPBs())
+			}
+		})
+	}
+}
+
+func TestBPS_IsMultipleOf(t *testing.T) {
+	tests := map[string]struct {
+		b    *bps.BPS
+		unit bps.Unit
+		want bool
+	}{
+		"3 basis points is a multiple of half basis point": {
+			bps.NewFromBasisPoint(3),
+			bps.HalfBasisPoint,
+			true,
+		},
+		"-3 basis points is a multiple of half basis point": {
+			bps.NewFromBasisPoint(-3),
+			bps.HalfBasisPoint,
+			true,
+		},
+		"3 deci basis points is not a multiple of half basis point": {
+			bps.NewFromDeciBasisPoint(3),
+			bps.HalfBasisPoint,
+			false,
+		},
+		"1 ppb is a multiple of ppb": {
+			bps.NewFromPPB(big.NewInt(1)),
+			bps.PPB,
+			true,
+		},
+		"nil is a multiple of any unit": {
+			&bps.BPS{},
+			bps.Unity,
+			true,
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.b.IsMultipleOf(tt.unit); got != tt.want {
+				t.Errorf("BPS.IsMultipleOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func ExampleBPS_RoundTo() {
+	// 2.645% * 14999 = 396.72355
+	fee := bps.NewFromDeciBasisPoint(2645).Mul(14999)
+	fmt.Println(fee.RoundTo(bps.Unity, bps.RoundHalfUp).Amounts())
+	fmt.Println(fee.TruncateTo(bps.Unity).Amounts())
+
+	// 0.0123% = 1.23 basis points, snap it on the half basis point grid
+	rate := bps.MustFromString("0.000123")
+	fmt.Println(rate.RoundTo(bps.HalfBasisPoint, bps.RoundHalfEven).HalfBasisPoints())
+	// Output:
+	// 397
+	// 396
+	// 2
+}
@@ -0,0 +1,275 @@
+package bps
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// make sure that the *Scaled implements some interfaces.
+var _ interface {
+	fmt.Stringer
+	sql.Scanner
+	driver.Valuer
+	encoding.TextMarshaler
+	encoding.TextUnmarshaler
+} = (*Scaled)(nil)
+
+// Scaled is a decimal amount with an arbitrary number of fractional digits called scale.
+// It's for values which need more precision than ppb, e.g. crypto-asset fees or intermediate interest accruals.
+// A Scaled with scale 9 has the same precision as BPS, and it interoperates with BPS by explicit rounding.
+//
+// Like BPS, the operations never mutate the receiver and return a new instance.
+// The zero value of Scaled is 0 with scale 0.
+type Scaled struct {
+	// value is the unscaled value, that means the amount is value / 10^scale.
+	value *big.Int
+	scale int
+}
+
+// NewScaled returns a new Scaled which means unscaled / 10^scale, e.g. NewScaled(big.NewInt(15), 2) is 0.15.
+// It panics if scale is negative.
+func NewScaled(unscaled *big.Int, scale int) *Scaled {
+	if scale < 0 {
+		panic("bps: negative scale")
+	}
+	v := new(big.Int)
+	if unscaled != nil {
+		v.Set(unscaled)
+	}
+	return &Scaled{value: v, scale: scale}
+}
+
+// NewScaledFromBPS returns a new Scaled from `b` with `scale`.
+// It's exact if scale is 9 or more, otherwise it's rounded by `mode`.
+func NewScaledFromBPS(b *BPS, scale int, mode RoundingMode) *Scaled {
+	return NewScaled(nilSafe(b).bigValue(), ppbDigits).Rescale(scale, mode)
+}
+
+// ParseScaled returns a new Scaled with `scale` from a string representation like NewFromString.
+// The digits beyond scale are rounded by `mode`.
+func ParseScaled(value string, scale int, mode RoundingMode) (*Scaled, error) {
+	if scale < 0 {
+		return nil, fmt.Errorf("can't convert %s to Scaled: negative scale %d", value, scale)
+	}
+	s, err := parseScaled(value)
+	if err != nil {
+		return nil, err
+	}
+	return s.Rescale(scale, mode), nil
+}
+
+// parseScaled returns a new Scaled which keeps all the fractional digits of value.
+func parseScaled(value string) (*Scaled, error) {
+	s := strings.TrimLeft(value, "+-")
+	if len(value)-len(s) > 1 {
+		return nil, fmt.Errorf("can't convert %s to Scaled", value)
+	}
+	scale := 0
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		scale = len(s) - i - 1
+		s = s[:i] + s[i+1:]
+	}
+	if s == "" || strings.Trim(s, "0123456789") != "" {
+		return nil, fmt.Errorf("can't convert %s to Scaled", value)
+	}
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("can't convert %s to Scaled", value)
+	}
+	if strings.HasPrefix(value, "-") {
+		v.Neg(v)
+	}
+	return &Scaled{value: v, scale: scale}, nil
+}
+
+// unscaled returns the unscaled value which must not be mutated.
+func (s *Scaled) unscaled() *big.Int {
+	if s == nil || s.value == nil {
+		return new(big.Int)
+	}
+	return s.value
+}
+
+// Scale returns the number of fractional digits of `s`.
+func (s *Scaled) Scale() int {
+	if s == nil {
+		return 0
+	}
+	return s.scale
+}
+
+// Unscaled returns the unscaled value of `s` as new big.Int instance, that means `s` is Unscaled() / 10^Scale().
+func (s *Scaled) Unscaled() *big.Int {
+	return new(big.Int).Set(s.unscaled())
+}
+
+// Rescale returns `s` with `scale`. It's exact if scale is not less than Scale(), otherwise it's rounded by `mode`.
+// It panics if scale is negative.
+func (s *Scaled) Rescale(scale int, mode RoundingMode) *Scaled {
+	if scale < 0 {
+		panic("bps: negative scale")
+	}
+	d := s.Scale() - scale
+	switch {
+	case d > 0:
+		return &Scaled{value: quoRound(s.unscaled(), pow10Big(d), mode), scale: scale}
+	case d < 0:
+		return &Scaled{value: new(big.Int).Mul(s.unscaled(), pow10Big(-d)), scale: scale}
+	}
+	return NewScaled(s.unscaled(), scale)
+}
+
+// BPS returns `s` as a new BPS rounded to ppb by `mode`.
+func (s *Scaled) BPS(mode RoundingMode) *BPS {
+	return newBPS(s.Rescale(ppbDigits, mode).value)
+}
+
+// Add returns s + s2 with the larger scale of them.
+func (s *Scaled) Add(s2 *Scaled) *Scaled {
+	x, y, scale := align(s, s2)
+	return &Scaled{value: x.Add(x, y), scale: scale}
+}
+
+// Sub returns s - s2 with the larger scale of them.
+func (s *Scaled) Sub(s2 *Scaled) *Scaled {
+	x, y, scale := align(s, s2)
+	return &Scaled{value: x.Sub(x, y), scale: scale}
+}
+
+// Mul returns s * i.
+func (s *Scaled) Mul(i int64) *Scaled {
+	return &Scaled{value: new(big.Int).Mul(s.unscaled(), big.NewInt(i)), scale: s.Scale()}
+}
+
+// MulBPS returns s * b exactly, that means the scale of the result is Scale() + 9.
+// e.g. a daily accrual is balance.MulBPS(rate).Div(365, scale, mode).
+func (s *Scaled) MulBPS(b *BPS) *Scaled {
+	return &Scaled{value: new(big.Int).Mul(s.unscaled(), nilSafe(b).bigValue()), scale: s.Scale() + ppbDigits}
+}
+
+// Div returns s / i with `scale` rounded by `mode`.
+// It panics if i is zero.
+func (s *Scaled) Div(i int64, scale int, mode RoundingMode) *Scaled {
+	if scale < 0 {
+		panic("bps: negative scale")
+	}
+	num, den := s.unscaled(), big.NewInt(i)
+	if d := scale - s.Scale(); d >= 0 {
+		num = new(big.Int).Mul(num, pow10Big(d))
+	} else {
+		den.Mul(den, pow10Big(-d))
+	}
+	return &Scaled{value: quoRound(num, den, mode), scale: scale}
+}
+
+// Cmp compares `s` and s2 and returns -1, 0 or +1.
+func (s *Scaled) Cmp(s2 *Scaled) int {
+	x, y, _ := align(s, s2)
+	return x.Cmp(y)
+}
+
+// Equal reports whether s == s2 regardless of their scales.
+func (s *Scaled) Equal(s2 *Scaled) bool {
+	return s.Cmp(s2) == 0
+}
+
+// Sign returns -1 if s < 0, 0 if s == 0 and +1 if s > 0.
+func (s *Scaled) Sign() int {
+	return s.unscaled().Sign()
+}
+
+// IsZero reports whether `s` is 0.
+func (s *Scaled) IsZero() bool {
+	return s.Sign() == 0
+}
+
+// Rat returns a rational number representation of `s`.
+func (s *Scaled) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(s.unscaled(), pow10Big(s.Scale()))
+}
+
+// String returns the decimal representation of `s` with exactly Scale() fractional digits like "0.000000000001".
+func (s *Scaled) String() string {
+	text, _ := s.AppendText(nil)
+	return string(text)
+}
+
+// AppendText appends the decimal representation of `s` like String to dst and returns the extended buffer.
+func (s *Scaled) AppendText(dst []byte) ([]byte, error) {
+	v := s.unscaled()
+	if v.Sign() < 0 {
+		dst = append(dst, '-')
+	}
+	digits := new(big.Int).Abs(v).String()
+	scale := s.Scale()
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+	dst = append(dst, digits[:len(digits)-scale]...)
+	if scale > 0 {
+		dst = append(dst, '.')
+		dst = append(dst, digits[len(digits)-scale:]...)
+	}
+	return dst, nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (s *Scaled) MarshalText() ([]byte, error) {
+	return s.AppendText(nil)
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// The scale is the number of fractional digits in text, so it round-trips MarshalText.
+func (s *Scaled) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return errors.New("Scaled.UnmarshalText: no data")
+	}
+	n, err := parseScaled(string(text))
+	if err != nil {
+		return err
+	}
+	*s = *n
+	return nil
+}
+
+// Value implements the driver.Valuer interface for database serialization.
+func (s *Scaled) Value() (driver.Value, error) {
+	return s.String(), nil
+}
+
+// Scan implements the sql.Scanner interface for database deserialization.
+// An integer is scanned as an integer amount with scale 0.
+func (s *Scaled) Scan(value interface{}) error {
+	if s == nil {
+		return errors.New("Scaled.Scan: nil receiver")
+	}
+	switch v := value.(type) {
+	case int64:
+		*s = Scaled{value: big.NewInt(v)}
+		return nil
+	case string:
+		return s.UnmarshalText([]byte(v))
+	case []byte:
+		return s.UnmarshalText(v)
+	}
+	return errors.New("Scaled.Scan: invalid type, supporting only int64, string or []byte")
+}
+
+// align returns the unscaled values of x and y as new big.Int instances in the larger scale of them.
+func align(x, y *Scaled) (*big.Int, *big.Int, int) {
+	scale := x.Scale()
+	if y.Scale() > scale {
+		scale = y.Scale()
+	}
+	return x.Rescale(scale, RoundDown).value, y.Rescale(scale, RoundDown).value, scale
+}
+
+// pow10Big returns 10^n as new big.Int instance.
+func pow10Big(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
@@ -0,0 +1,225 @@
+package bps_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"go.mercari.io/go-bps/bps"
+)
+
+func TestParseScaled(t *testing.T) {
+	tests := map[string]struct {
+		value   string
+		scale   int
+		mode    bps.RoundingMode
+		want    string
+		wantErr bool
+	}{
+		"18 fractional digits": {
+			"0.000000000000000001",
+			18,
+			bps.RoundDown,
+			"0.000000000000000001",
+			false,
+		},
+		"padded to the scale": {
+			"1.5",
+			12,
+			bps.RoundDown,
+			"1.500000000000",
+			false,
+		},
+		"rounded half up to the scale": {
+			"-0.0000000000005",
+			12,
+			bps.RoundHalfUp,
+			"-0.000000000001",
+			false,
+		},
+		"rounded half even to the scale": {
+			"0.0000000000005",
+			12,
+			bps.RoundHalfEven,
+			"0.000000000000",
+			false,
+		},
+		"integer with scale 0": {
+			"+15",
+			0,
+			bps.RoundDown,
+			"15",
+			false,
+		},
+		"If negative scale, it should return an error": {
+			"1",
+			-1,
+			bps.RoundDown,
+			"",
+			true,
+		},
+		"If invalid string, it should return an error": {
+			"1.2.3",
+			2,
+			bps.RoundDown,
+			"",
+			true,
+		},
+		"If multiple signs, it should return an error": {
+			"-+1",
+			2,
+			bps.RoundDown,
+			"",
+			true,
+		},
+		"If only a decimal point, it should return an error": {
+			"-.",
+			2,
+			bps.RoundDown,
+			"",
+			true,
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := bps.ParseScaled(tt.value, tt.scale, tt.mode)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseScaled() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got.String() != tt.want {
+				t.Errorf("ParseScaled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScaled_BPS(t *testing.T) {
+	t.Parallel()
+
+	b := bps.NewFromDeciBasisPoint(2645)
+	s := bps.NewScaledFromBPS(b, 18, bps.RoundDown)
+	if got, want := s.String(), "0.026450000000000000"; got != want {
+		t.Errorf("NewScaledFromBPS() = %v, want %v", got, want)
+	}
+	if got := s.BPS(bps.RoundDown); !got.Equal(b) {
+		t.Errorf("Scaled.BPS() = %v, want %v", got.PPBs(), b.PPBs())
+	}
+
+	if got, want := bps.NewScaledFromBPS(b, 3, bps.RoundHalfUp).String(), "0.026"; got != want {
+		t.Errorf("NewScaledFromBPS() = %v, want %v", got, want)
+	}
+
+	tiny := bps.NewScaled(big.NewInt(15), 19)
+	if got := tiny.BPS(bps.RoundDown); !got.IsZero() {
+		t.Errorf("Scaled.BPS() = %v, want 0", got.PPBs())
+	}
+	if got := tiny.BPS(bps.RoundUp); !got.Equal(bps.NewFromPPB(big.NewInt(1))) {
+		t.Errorf("Scaled.BPS() = %v, want 1", got.PPBs())
+	}
+}
+
+func TestScaled_Arithmetic(t *testing.T) {
+	t.Parallel()
+
+	a, _ := bps.ParseScaled("1.000000000000001", 15, bps.RoundDown)
+	b, _ := bps.ParseScaled("0.5", 1, bps.RoundDown)
+
+	if got, want := a.Add(b).String(), "1.500000000000001"; got != want {
+		t.Errorf("Scaled.Add() = %v, want %v", got, want)
+	}
+	if got, want := b.Sub(a).String(), "-0.500000000000001"; got != want {
+		t.Errorf("Scaled.Sub() = %v, want %v", got, want)
+	}
+	if got, want := a.Mul(-3).String(), "-3.000000000000003"; got != want {
+		t.Errorf("Scaled.Mul() = %v, want %v", got, want)
+	}
+	if got, want := b.Div(3, 12, bps.RoundHalfUp).String(), "0.166666666667"; got != want {
+		t.Errorf("Scaled.Div() = %v, want %v", got, want)
+	}
+	if got, want := a.Div(2, 3, bps.RoundHalfUp).String(), "0.500"; got != want {
+		t.Errorf("Scaled.Div() = %v, want %v", got, want)
+	}
+	if got := a.Cmp(b); got != 1 {
+		t.Errorf("Scaled.Cmp() = %v, want 1", got)
+	}
+	if !b.Equal(bps.NewScaled(big.NewInt(500), 3)) {
+		t.Error("Scaled.Equal() should ignore the scales")
+	}
+	if got, want := a.Rat(), big.NewRat(1000000000000001, 1000000000000000); got.Cmp(want) != 0 {
+		t.Errorf("Scaled.Rat() = %v, want %v", got, want)
+	}
+	var zero bps.Scaled
+	if !zero.IsZero() || zero.Scale() != 0 || zero.String() != "0" {
+		t.Errorf("the zero value of Scaled should be 0, got %v", &zero)
+	}
+}
+
+func TestScaled_MulBPS(t *testing.T) {
+	t.Parallel()
+
+	// daily accrual of 14999 at 2.645% per year
+	balance := bps.NewScaled(big.NewInt(14999), 0)
+	rate := bps.NewFromDeciBasisPoint(2645)
+	daily := balance.MulBPS(rate).Div(365, 18, bps.RoundHalfEven)
+	if got, want := daily.String(), "1.086913835616438356"; got != want {
+		t.Errorf("daily accrual = %v, want %v", got, want)
+	}
+	if got, want := daily.BPS(bps.RoundDown), bps.MustFromString("1.086913835"); !got.Equal(want) {
+		t.Errorf("Scaled.BPS() = %v, want %v", got.PPBs(), want.PPBs())
+	}
+}
+
+func TestScaled_Serialization(t *testing.T) {
+	t.Parallel()
+
+	type Accrual struct {
+		Amount *bps.Scaled `json:"amount"`
+	}
+	s, _ := bps.ParseScaled("-1.086913835616438356", 18, bps.RoundDown)
+	data, err := json.Marshal(Accrual{Amount: s})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"amount":"-1.086913835616438356"}`; string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+	var got Accrual
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !got.Amount.Equal(s) || got.Amount.Scale() != 18 {
+		t.Errorf("json.Unmarshal() = %v, want %v", got.Amount, s)
+	}
+
+	v, _ := s.Value()
+	var scanned bps.Scaled
+	if err := scanned.Scan(v); err != nil || !scanned.Equal(s) {
+		t.Errorf("Scaled.Scan() = %v, %v, want %v", &scanned, err, s)
+	}
+	if err := scanned.Scan(int64(15)); err != nil || scanned.String() != "15" {
+		t.Errorf("Scaled.Scan() = %v, %v, want 15", &scanned, err)
+	}
+	if err := scanned.Scan(1.5); err == nil {
+		t.Error("Scaled.Scan() should return an error for float64")
+	}
+	if err := scanned.UnmarshalText(nil); err == nil {
+		t.Error("Scaled.UnmarshalText() should return an error for empty")
+	}
+}
+
+func ExampleScaled() {
+	balance := bps.NewScaled(big.NewInt(14999), 0)
+	rate := bps.NewFromDeciBasisPoint(2645)
+
+	// keep 18 fractional digits for the intermediate daily accrual
+	daily := balance.MulBPS(rate).Div(365, 18, bps.RoundHalfEven)
+	fmt.Println(daily)
+	fmt.Println(daily.BPS(bps.RoundHalfUp).FloatString(9))
+	// Output:
+	// 1.086913835616438356
+	// 1.086913836
+}
@@ -0,0 +1,139 @@
+package bps
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Schema is a JSON Schema fragment which describes a representation of BPS.
+// It's also a valid OpenAPI schema object, and encoding/json marshals it into the schema.
+type Schema struct {
+	Type        string `json:"type"`
+	Format      string `json:"format,omitempty"`
+	Pattern     string `json:"pattern,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Minimum and Maximum are set only for an integer representation, since they don't apply to a string in JSON Schema.
+	Minimum *json.Number `json:"minimum,omitempty"`
+	Maximum *json.Number `json:"maximum,omitempty"`
+	// Example is for OpenAPI 3.0, and Examples is for JSON Schema and OpenAPI 3.1.
+	Example  interface{}   `json:"example,omitempty"`
+	Examples []interface{} `json:"examples,omitempty"`
+}
+
+// Patterns of the string representations, which allow up to ppb precision.
+const (
+	decimalPattern = `^[+-]?[0-9]+(\.[0-9]{1,9})?$`
+	percentPattern = `^[+-]?[0-9]+(\.[0-9]{1,7})?%$`
+)
+
+// JSONSchema returns the schema of the representation `f`.
+func JSONSchema(f Format) *Schema {
+	example := NewFromDeciBasisPoint(2645)
+	var s *Schema
+	switch f {
+	case FormatPercent:
+		s = &Schema{
+			Type:        "string",
+			Format:      "percent",
+			Pattern:     percentPattern,
+			Description: "A percentage with up to 7 fractional digits, that means ppb precision.",
+			Example:     example.UnitString(Percentage),
+		}
+	case FormatBaseUnit:
+		s = &Schema{
+			Type:        "integer",
+			Format:      "int64",
+			Description: fmt.Sprintf("An integer count of %s.", unitName(BaseUnit)),
+			Example:     example.BaseUnitAmounts().Int64(),
+		}
+	default:
+		s = &Schema{
+			Type:        "string",
+			Format:      "decimal",
+			Pattern:     decimalPattern,
+			Description: "A decimal amount with up to 9 fractional digits, that means ppb precision. 1 means 100%.",
+			Example:     example.CanonicalString(),
+		}
+	}
+	s.Examples = []interface{}{s.Example}
+	return s
+}
+
+// unitName returns the name of `u` for the human-readable descriptions.
+func unitName(u Unit) string {
+	switch u {
+	case PPM:
+		return "ppm"
+	case DeciBasisPoint:
+		return "deci basis points"
+	case HalfBasisPoint:
+		return "half basis points"
+	case BasisPoint:
+		return "basis points"
+	case Percentage:
+		return "percentages"
+	case Unity:
+		return "amounts"
+	}
+	return "ppb"
+}
+
+// WithRange returns a copy of `s` which documents the inclusive range [min, max] in the representation of `s`.
+// A nil bound means unbounded.
+// The bounds are Minimum and Maximum for FormatBaseUnit, otherwise they are described in Description
+// because JSON Schema has no keyword to limit a decimal string.
+func (s *Schema) WithRange(min, max *BPS) *Schema {
+	res := *s
+	res.Examples = append([]interface{}{}, s.Examples...)
+	if res.Type == "integer" {
+		if min != nil {
+			n := json.Number(min.BaseUnitAmounts().String())
+			res.Minimum = &n
+		}
+		if max != nil {
+			n := json.Number(max.BaseUnitAmounts().String())
+			res.Maximum = &n
+		}
+		return &res
+	}
+
+	str := func(b *BPS) string {
+		if res.Format == "percent" {
+			return b.UnitString(Percentage)
+		}
+		return b.CanonicalString()
+	}
+	var r string
+	switch {
+	case min != nil && max != nil:
+		r = fmt.Sprintf("It's between %s and %s inclusive.", str(min), str(max))
+	case min != nil:
+		r = fmt.Sprintf("It's %s or more.", str(min))
+	case max != nil:
+		r = fmt.Sprintf("It's %s or less.", str(max))
+	default:
+		return &res
+	}
+	if res.Description != "" {
+		r = res.Description + " " + r
+	}
+	res.Description = r
+	return &res
+}
+
+// JSONSchema returns the schema of BPS in encoding/json, which is always represented as a decimal string by MarshalJSON.
+// MarshalFormat doesn't affect it since it's only for YAML and XML.
+// It doesn't depend on the value of `b`, so schema generators can call it on the zero value.
+func (b *BPS) JSONSchema() *Schema {
+	return JSONSchema(FormatDecimal)
+}
+
+// JSONSchema returns the schema of Rate, which is always represented as a decimal string.
+func (r Rate) JSONSchema() *Schema {
+	return JSONSchema(FormatDecimal)
+}
+
+// JSONSchema returns the schema of Amount, which is always represented as a decimal string.
+func (a Amount) JSONSchema() *Schema {
+	return JSONSchema(FormatDecimal)
+}
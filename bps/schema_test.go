@@ -0,0 +1,183 @@
+package bps_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"testing"
+
+	"go.mercari.io/go-bps/bps"
+)
+
+func TestJSONSchema(t *testing.T) {
+	tests := map[string]struct {
+		format bps.Format
+		want   string
+	}{
+		"decimal": {
+			bps.FormatDecimal,
+			`{"type":"string","format":"decimal","pattern":"^[+-]?[0-9]+(\\.[0-9]{1,9})?$","description":"A decimal amount with up to 9 fractional digits, that means ppb precision. 1 means 100%.","example":"0.02645","examples":["0.02645"]}`,
+		},
+		"percent": {
+			bps.FormatPercent,
+			`{"type":"string","format":"percent","pattern":"^[+-]?[0-9]+(\\.[0-9]{1,7})?%$","description":"A percentage with up to 7 fractional digits, that means ppb precision.","example":"2.645%","examples":["2.645%"]}`,
+		},
+		"base unit": {
+			bps.FormatBaseUnit,
+			`{"type":"integer","format":"int64","description":"An integer count of deci basis points.","example":2645,"examples":[2645]}`,
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := json.Marshal(bps.JSONSchema(tt.format))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("JSONSchema() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONSchema_Pattern(t *testing.T) {
+	t.Parallel()
+
+	decimal := regexp.MustCompile(bps.JSONSchema(bps.FormatDecimal).Pattern)
+	percent := regexp.MustCompile(bps.JSONSchema(bps.FormatPercent).Pattern)
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		b := randomBPS(r)
+		if s := b.CanonicalString(); !decimal.MatchString(s) {
+			t.Errorf("decimal pattern doesn't match %s", s)
+		}
+		if s := b.UnitString(bps.Percentage); !percent.MatchString(s) {
+			t.Errorf("percent pattern doesn't match %s", s)
+		}
+	}
+	for _, s := range []string{"0.0000000001", "1e-3", "1.", "%"} {
+		if decimal.MatchString(s) {
+			t.Errorf("decimal pattern matches %s", s)
+		}
+	}
+}
+
+func TestJSONSchema_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	pattern := regexp.MustCompile((*bps.BPS)(nil).JSONSchema().Pattern)
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		b := randomBPS(r)
+		data, err := json.Marshal(struct {
+			P *bps.BPS
+			V bps.BPS
+		}{b, *b})
+		if err != nil {
+			t.Fatalf("json.Marshal() error = %v", err)
+		}
+		var got struct{ P, V string }
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("json.Marshal() = %s, want strings: %v", data, err)
+		}
+		if !pattern.MatchString(got.P) || !pattern.MatchString(got.V) {
+			t.Errorf("json.Marshal() = %s, which doesn't match %s", data, pattern)
+		}
+		var back struct{ P, V *bps.BPS }
+		if err := json.Unmarshal(data, &back); err != nil || !back.P.Equal(b) || !back.V.Equal(b) {
+			t.Errorf("json.Unmarshal(%s) = %v, %v, error = %v", data, back.P, back.V, err)
+		}
+	}
+}
+
+func TestSchema_WithRange(t *testing.T) {
+	tests := map[string]struct {
+		schema   *bps.Schema
+		min, max *bps.BPS
+		want     string
+	}{
+		"integer": {
+			bps.JSONSchema(bps.FormatBaseUnit),
+			bps.NewFromAmount(0),
+			bps.NewFromPercentage(100),
+			`"minimum":0,"maximum":100000,`,
+		},
+		"decimal": {
+			bps.JSONSchema(bps.FormatDecimal),
+			bps.NewFromAmount(0),
+			bps.NewFromPercentage(100),
+			`1 means 100%. It's between 0 and 1 inclusive."`,
+		},
+		"percent with only min": {
+			bps.JSONSchema(bps.FormatPercent),
+			bps.NewFromBasisPoint(-50),
+			nil,
+			`that means ppb precision. It's -0.5% or more."`,
+		},
+		"percent with only max": {
+			bps.JSONSchema(bps.FormatPercent),
+			nil,
+			bps.NewFromBasisPoint(50),
+			`that means ppb precision. It's 0.5% or less."`,
+		},
+		"unbounded": {
+			bps.JSONSchema(bps.FormatPercent),
+			nil,
+			nil,
+			`that means ppb precision."`,
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			before, _ := json.Marshal(tt.schema)
+			got, err := json.Marshal(tt.schema.WithRange(tt.min, tt.max))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !regexp.MustCompile(regexp.QuoteMeta(tt.want)).Match(got) {
+				t.Errorf("Schema.WithRange() = %s, want to contain %s", got, tt.want)
+			}
+			if after, _ := json.Marshal(tt.schema); string(after) != string(before) {
+				t.Errorf("Schema.WithRange() should not modify the receiver, got %s", after)
+			}
+		})
+	}
+}
+
+func TestJSONSchema_Methods(t *testing.T) {
+	setMarshalFormat(t, bps.FormatPercent)
+
+	// MarshalFormat doesn't affect encoding/json
+	var b *bps.BPS
+	if got := b.JSONSchema().Format; got != "decimal" {
+		t.Errorf("BPS.JSONSchema().Format = %v, want decimal", got)
+	}
+	if got := (bps.Rate{}).JSONSchema().Format; got != "decimal" {
+		t.Errorf("Rate.JSONSchema().Format = %v, want decimal", got)
+	}
+	if got := (bps.Amount{}).JSONSchema().Format; got != "decimal" {
+		t.Errorf("Amount.JSONSchema().Format = %v, want decimal", got)
+	}
+}
+
+func ExampleJSONSchema() {
+	s := bps.JSONSchema(bps.FormatPercent).WithRange(bps.NewFromAmount(0), bps.NewFromPercentage(100))
+	data, _ := json.MarshalIndent(s, "", "  ")
+	fmt.Println(string(data))
+	// Output:
+	// {
+	//   "type": "string",
+	//   "format": "percent",
+	//   "pattern": "^[+-]?[0-9]+(\\.[0-9]{1,7})?%$",
+	//   "description": "A percentage with up to 7 fractional digits, that means ppb precision. It's between 0% and 100% inclusive.",
+	//   "example": "2.645%",
+	//   "examples": [
+	//     "2.645%"
+	//   ]
+	// }
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"encoding"
+	"encoding/json"
 	"errors"
 )
 
@@ -14,6 +15,10 @@ var _ interface {
 	encoding.TextUnmarshaler
 } = (*BPS)(nil)
 
+var _ encoding.TextMarshaler = (*BPS)(nil)
+
+var _ json.Marshaler = BPS{}
+
 // Scan implements the sql.Scanner interface for database deserialization.
 func (b *BPS) Scan(value interface{}) error {
 	if b == nil {
@@ -22,35 +27,35 @@ func (b *BPS) Scan(value interface{}) error {
 
 	switch v := value.(type) {
 	case uint:
-		s := NewFromBaseUnit(int64(v))
-		b.value = s.value
+		s := NewFromUint64In(BaseUnit, uint64(v))
+		*b = *s
 		return nil
 	case uint32:
 		s := NewFromBaseUnit(int64(v))
-		b.value = s.value
+		*b = *s
 		return nil
 	case uint64:
-		s := NewFromBaseUnit(int64(v))
-		b.value = s.value
+		s := NewFromUint64In(BaseUnit, v)
+		*b = *s
 		return nil
 	case int:
 		s := NewFromBaseUnit(int64(v))
-		b.value = s.value
+		*b = *s
 		return nil
 	case int32:
 		s := NewFromBaseUnit(int64(v))
-		b.value = s.value
+		*b = *s
 		return nil
 	case int64:
 		s := NewFromBaseUnit(v)
-		b.value = s.value
+		*b = *s
 		return nil
 	case string:
 		s, err := NewFromString(v)
 		if err != nil {
 			return err
 		}
-		b.value = s.value
+		*b = *s
 		return nil
 	}
 
@@ -62,18 +67,28 @@ func (b *BPS) Value() (driver.Value, error) {
 	return b.String(), nil
 }
 
-// UnmarshalText implements the encoding.TextUnmarshaler interface.
-func (b *BPS) UnmarshalText(text []byte) error {
-	v := string(text)
-	if v == "" {
-		return errors.New("BPS.UnmarshalText: no data")
-	}
+// MarshalText implements the encoding.TextMarshaler interface.
+// It uses the canonical string representation like "0.02645", which doesn't depend on BaseUnit or MarshalFormat.
+func (b *BPS) MarshalText() ([]byte, error) {
+	return nilSafe(b).AppendText(nil)
+}
 
-	n, err := NewFromString(v)
+// MarshalJSON implements the json.Marshaler interface, so encoding/json represents BPS as a string like "0.02645".
+// It uses the canonical string representation like MarshalText.
+// It has a value receiver to marshal a BPS field as well as a *BPS field, and encoding/json writes null for a nil *BPS.
+func (b BPS) MarshalJSON() ([]byte, error) {
+	buf := append(make([]byte, 0, 24), '"')
+	buf, err := b.AppendText(buf)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	b.value = n.value
+	return append(buf, '"'), nil
+}
 
-	return nil
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (b *BPS) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return errors.New("BPS.UnmarshalText: no data")
+	}
+	return b.parse(text)
 }
@@ -2,6 +2,8 @@ package bps_test
 
 import (
 	"database/sql/driver"
+	"encoding/json"
+	"math"
 	"reflect"
 	"testing"
 
@@ -68,6 +70,12 @@ func TestBPS_Scan(t *testing.T) {
 			bps.NewFromDeciBasisPoint(7),
 			false,
 		},
+		"If value is max uint64, it should set value without overflow": {
+			&bps.BPS{},
+			uint64(math.MaxUint64),
+			bps.NewFromUint64In(bps.DeciBasisPoint, math.MaxUint64),
+			false,
+		},
 		"If value is int, it should set value as DeciBasisPoint": {
 			&bps.BPS{},
 			int(6),
@@ -171,3 +179,41 @@ func TestBPS_UnmarshalText(t *testing.T) {
 		})
 	}
 }
+
+func TestBPS_MarshalText(t *testing.T) {
+	tests := map[string]struct {
+		b    *bps.BPS
+		want string
+	}{
+		"2.645%":      {bps.NewFromDeciBasisPoint(2645), "0.02645"},
+		"negative":    {bps.NewFromAmount(-3), "-3"},
+		"nil is zero": {nil, "0"},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tt.b.MarshalText()
+			if err != nil || string(got) != tt.want {
+				t.Errorf("BPS.MarshalText() = %s, %v, want %s", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestBPS_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	v := struct {
+		P    *bps.BPS `json:"p"`
+		V    bps.BPS  `json:"v"`
+		Null *bps.BPS `json:"null"`
+	}{P: bps.NewFromDeciBasisPoint(2645), V: *bps.NewFromPercentage(10)}
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"p":"0.02645","v":"0.1","null":null}`; string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+}
@@ -0,0 +1,201 @@
+package bps
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Step is an operation recorded by Tracer. The values are represented as exact decimal amounts like "396.72355".
+type Step struct {
+	// Op is the name of the operation like "Mul".
+	Op string `json:"op"`
+	// Operands are the operands of the operation.
+	Operands []string `json:"operands"`
+	// Exact is the exact result before rounding. It's a fraction like "1/3" if it has no finite decimal representation.
+	Exact string `json:"exact"`
+	// Result is the result of the operation.
+	Result string `json:"result"`
+	// Rounding describes the rounding applied like "floor to 1ppb", or empty if Result is Exact.
+	Rounding string `json:"rounding,omitempty"`
+}
+
+// String returns the text representation of `s` like "Mul(0.02645, 14999) = 396.72355".
+func (s Step) String() string {
+	text := fmt.Sprintf("%s(%s) = %s", s.Op, strings.Join(s.Operands, ", "), s.Result)
+	if s.Rounding != "" {
+		text += fmt.Sprintf(" (exact %s, rounded %s)", s.Exact, s.Rounding)
+	}
+	return text
+}
+
+// Tracer records the operations of Context.
+type Tracer interface {
+	Trace(s Step)
+}
+
+// Trace is a Tracer which keeps the steps in order. It's not safe for concurrent use.
+type Trace struct {
+	Steps []Step
+}
+
+// Trace implements the Tracer interface.
+func (t *Trace) Trace(s Step) {
+	t.Steps = append(t.Steps, s)
+}
+
+// String returns the steps one per line for audit logs.
+func (t *Trace) String() string {
+	var sb strings.Builder
+	for i, s := range t.Steps {
+		fmt.Fprintf(&sb, "%d. %s\n", i+1, s)
+	}
+	return sb.String()
+}
+
+// MarshalJSON implements the json.Marshaler interface. It returns the array of the steps.
+func (t *Trace) MarshalJSON() ([]byte, error) {
+	if t.Steps == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(t.Steps)
+}
+
+// Context performs the operations of BPS and records them to Tracer, so it can explain how a value was derived,
+// e.g. which truncation made a fee 396 instead of 397:
+//
+//	var trace bps.Trace
+//	ctx := bps.Context{Tracer: &trace}
+//	fee := ctx.Amounts(ctx.Mul(rate, 14999))
+//	log.Print(trace.String())
+//
+// The results are the same as the methods of BPS. The zero value of Context records nothing.
+type Context struct {
+	Tracer Tracer
+}
+
+// Add returns x + y like BPS.Add.
+func (c Context) Add(x, y *BPS) *BPS {
+	res := x.Add(y)
+	if c.Tracer != nil {
+		c.trace("Add", res.Rat(), res.CanonicalString(), "", x.CanonicalString(), y.CanonicalString())
+	}
+	return res
+}
+
+// Sub returns x - y like BPS.Sub.
+func (c Context) Sub(x, y *BPS) *BPS {
+	res := x.Sub(y)
+	if c.Tracer != nil {
+		c.trace("Sub", res.Rat(), res.CanonicalString(), "", x.CanonicalString(), y.CanonicalString())
+	}
+	return res
+}
+
+// Mul returns x * i like BPS.Mul.
+func (c Context) Mul(x *BPS, i int64) *BPS {
+	res := x.Mul(i)
+	if c.Tracer != nil {
+		c.trace("Mul", res.Rat(), res.CanonicalString(), "", x.CanonicalString(), strconv.FormatInt(i, 10))
+	}
+	return res
+}
+
+// Div returns x / i like BPS.Div.
+func (c Context) Div(x *BPS, i int64) *BPS {
+	res := x.Div(i)
+	if c.Tracer != nil {
+		exact := new(big.Rat).Quo(x.Rat(), new(big.Rat).SetInt64(i))
+		// BPS.Div is Euclidean division, which rounds to floor for a positive divisor and to ceiling for a negative one
+		mode := RoundFloor
+		if i < 0 {
+			mode = RoundCeiling
+		}
+		c.trace("Div", exact, res.CanonicalString(), rounding(mode, PPB), x.CanonicalString(), strconv.FormatInt(i, 10))
+	}
+	return res
+}
+
+// RoundTo returns `x` rounded to a multiple of `u` by `mode` like BPS.RoundTo.
+func (c Context) RoundTo(x *BPS, u Unit, mode RoundingMode) *BPS {
+	res := x.RoundTo(u, mode)
+	if c.Tracer != nil {
+		c.trace("RoundTo", x.Rat(), res.CanonicalString(), rounding(mode, u), x.CanonicalString(), unitOne(u), modeNames[mode])
+	}
+	return res
+}
+
+// Amounts returns `x` as an integer amount like BPS.Amounts, which is rounded to floor.
+func (c Context) Amounts(x *BPS) int64 {
+	res := x.Amounts()
+	if c.Tracer != nil {
+		c.trace("Amounts", x.Rat(), strconv.FormatInt(res, 10), rounding(RoundFloor, Unity), x.CanonicalString())
+	}
+	return res
+}
+
+// MulRate returns the amount of `a` at the rate `r` like Rate.Mul, which is rounded down to ppb.
+func (c Context) MulRate(r Rate, a Amount) Amount {
+	res := r.Mul(a)
+	if c.Tracer != nil {
+		exact := new(big.Rat).Mul(r.v.Rat(), a.v.Rat())
+		c.trace("MulRate", exact, res.String(), rounding(RoundFloor, PPB), r.String(), a.String())
+	}
+	return res
+}
+
+// trace records the step. rounding is cleared if the result is exact.
+func (c Context) trace(op string, exact *big.Rat, result, rounding string, operands ...string) {
+	s := Step{Op: op, Operands: operands, Exact: ratString(exact), Result: result}
+	if s.Exact != s.Result {
+		s.Rounding = rounding
+	}
+	c.Tracer.Trace(s)
+}
+
+var modeNames = map[RoundingMode]string{
+	RoundDown:     "down",
+	RoundUp:       "up",
+	RoundFloor:    "floor",
+	RoundCeiling:  "ceiling",
+	RoundHalfUp:   "half up",
+	RoundHalfDown: "half down",
+	RoundHalfEven: "half even",
+}
+
+// rounding returns the description of the rounding like "half up to 1bp".
+func rounding(mode RoundingMode, u Unit) string {
+	return modeNames[mode] + " to " + unitOne(u)
+}
+
+// unitOne returns one `u` like "1bp".
+func unitOne(u Unit) string {
+	return "1" + u.String()
+}
+
+// ratString returns the shortest exact decimal representation of r, or a fraction like "1/3" if it has none.
+func ratString(r *big.Rat) string {
+	// a decimal is finite iff the denominator has no prime factors other than 2 and 5
+	d := new(big.Int).Set(r.Denom())
+	digits := 0
+	for _, p := range []int64{2, 5} {
+		n := 0
+		m, bp := new(big.Int), big.NewInt(p)
+		for {
+			q, rem := new(big.Int).QuoRem(d, bp, m)
+			if rem.Sign() != 0 {
+				break
+			}
+			d, n = q, n+1
+		}
+		if n > digits {
+			digits = n
+		}
+	}
+	if d.Cmp(one) != 0 {
+		return r.RatString()
+	}
+	return r.FloatString(digits)
+}
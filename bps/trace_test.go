@@ -0,0 +1,120 @@
+package bps_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"go.mercari.io/go-bps/bps"
+)
+
+func TestContext(t *testing.T) {
+	rate := bps.NewFromDeciBasisPoint(2645)
+	tests := map[string]struct {
+		f    func(ctx bps.Context) interface{}
+		want interface{}
+		step bps.Step
+	}{
+		"Add": {
+			func(ctx bps.Context) interface{} { return ctx.Add(rate, bps.NewFromPercentage(1)) },
+			bps.NewFromDeciBasisPoint(3645),
+			bps.Step{Op: "Add", Operands: []string{"0.02645", "0.01"}, Exact: "0.03645", Result: "0.03645"},
+		},
+		"Sub": {
+			func(ctx bps.Context) interface{} { return ctx.Sub(rate, bps.NewFromPercentage(3)) },
+			bps.NewFromDeciBasisPoint(-355),
+			bps.Step{Op: "Sub", Operands: []string{"0.02645", "0.03"}, Exact: "-0.00355", Result: "-0.00355"},
+		},
+		"Mul": {
+			func(ctx bps.Context) interface{} { return ctx.Mul(rate, 14999) },
+			rate.Mul(14999),
+			bps.Step{Op: "Mul", Operands: []string{"0.02645", "14999"}, Exact: "396.72355", Result: "396.72355"},
+		},
+		"Div rounded to floor": {
+			func(ctx bps.Context) interface{} { return ctx.Div(bps.NewFromAmount(1), 3) },
+			bps.NewFromAmount(1).Div(3),
+			bps.Step{Op: "Div", Operands: []string{"1", "3"}, Exact: "1/3", Result: "0.333333333", Rounding: "floor to 1ppb"},
+		},
+		"Div by a negative number rounded to ceiling": {
+			func(ctx bps.Context) interface{} { return ctx.Div(bps.NewFromAmount(1), -3) },
+			bps.NewFromAmount(1).Div(-3),
+			bps.Step{Op: "Div", Operands: []string{"1", "-3"}, Exact: "-1/3", Result: "-0.333333333", Rounding: "ceiling to 1ppb"},
+		},
+		"exact Div": {
+			func(ctx bps.Context) interface{} { return ctx.Div(bps.NewFromAmount(1), 4) },
+			bps.NewFromPercentage(25),
+			bps.Step{Op: "Div", Operands: []string{"1", "4"}, Exact: "0.25", Result: "0.25"},
+		},
+		"RoundTo": {
+			func(ctx bps.Context) interface{} { return ctx.RoundTo(rate, bps.BasisPoint, bps.RoundHalfEven) },
+			bps.NewFromBasisPoint(264),
+			bps.Step{Op: "RoundTo", Operands: []string{"0.02645", "1bp", "half even"}, Exact: "0.02645", Result: "0.0264", Rounding: "half even to 1bp"},
+		},
+		"Amounts": {
+			func(ctx bps.Context) interface{} { return ctx.Amounts(rate.Mul(14999)) },
+			int64(396),
+			bps.Step{Op: "Amounts", Operands: []string{"396.72355"}, Exact: "396.72355", Result: "396", Rounding: "floor to 1"},
+		},
+		"MulRate": {
+			func(ctx bps.Context) interface{} {
+				return ctx.MulRate(bps.RateFromBPS(bps.NewFromPPB(big.NewInt(1))), bps.AmountFromBPS(bps.NewFromPercentage(50)))
+			},
+			bps.AmountFromBPS(bps.NewFromAmount(0)),
+			bps.Step{Op: "MulRate", Operands: []string{"0.000000001", "0.5"}, Exact: "0.0000000005", Result: "0", Rounding: "floor to 1ppb"},
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var trace bps.Trace
+			got := tt.f(bps.Context{Tracer: &trace})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("result = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(trace.Steps, []bps.Step{tt.step}) {
+				t.Errorf("steps = %+v, want %+v", trace.Steps, tt.step)
+			}
+			// the zero value of Context records nothing and returns the same result
+			if got := tt.f(bps.Context{}); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("result without tracer = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrace_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	var trace bps.Trace
+	if data, _ := json.Marshal(&trace); string(data) != "[]" {
+		t.Errorf("json.Marshal() = %s, want []", data)
+	}
+	ctx := bps.Context{Tracer: &trace}
+	ctx.Amounts(ctx.Mul(bps.NewFromDeciBasisPoint(2645), 14999))
+	data, err := json.Marshal(&trace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"op":"Mul","operands":["0.02645","14999"],"exact":"396.72355","result":"396.72355"},` +
+		`{"op":"Amounts","operands":["396.72355"],"exact":"396.72355","result":"396","rounding":"floor to 1"}]`
+	if string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+}
+
+func ExampleContext() {
+	var trace bps.Trace
+	ctx := bps.Context{Tracer: &trace}
+
+	// why was the fee 396 and not 397?
+	fee := ctx.Amounts(ctx.Mul(bps.NewFromDeciBasisPoint(2645), 14999))
+	fmt.Println(fee)
+	fmt.Print(trace.String())
+	// Output:
+	// 396
+	// 1. Mul(0.02645, 14999) = 396.72355
+	// 2. Amounts(396.72355) = 396 (exact 396.72355, rounded floor to 1)
+}
@@ -0,0 +1,266 @@
+// Copyright © 2020 Merpay, Inc. All rights reserved.
+
+// Package bpscsv reads and writes BPS columns of CSV files.
+//
+// Each column is configured by Column, which has the unit of the values, e.g. "2.645" in a Percentage column means 2.645%.
+// Reader and Writer process one record at a time on top of encoding/csv, so they can stream large files.
+package bpscsv // import "go.mercari.io/go-bps/bpscsv"
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"go.mercari.io/go-bps/bps"
+)
+
+// Column configures how a BPS column is read and written.
+type Column struct {
+	// Name is the header of the column. If it's empty, Index is used.
+	Name string
+	// Index is the 0-based index of the column used if Name is empty.
+	Index int
+	// Unit is the unit of a value without a unit symbol. Default is bps.Unity, that means an amount like "0.02645".
+	// A value with a unit symbol like "264.5bp" is read by the symbol regardless of Unit.
+	Unit bps.Unit
+	// Prec is the number of fractional digits to write, and the last digit is rounded half away from zero.
+	// If it's 0, the shortest exact representation is written unless Fixed is set.
+	Prec int
+	// Fixed reports whether exactly Prec digits are written even if Prec is 0, e.g. to write whole basis points.
+	Fixed bool
+	// Symbol reports whether the symbol of Unit is written like "2.645%".
+	Symbol bool
+}
+
+// unit returns Unit or bps.Unity by default.
+func (c Column) unit() bps.Unit {
+	if c.Unit == 0 {
+		return bps.Unity
+	}
+	return c.Unit
+}
+
+// Parse returns a new BPS from a field of the column, or nil if the field is empty.
+func (c Column) Parse(field string) (*bps.BPS, error) {
+	s := strings.TrimSpace(field)
+	if s == "" {
+		return nil, nil
+	}
+	if last := s[len(s)-1]; last == '.' || ('0' <= last && last <= '9') {
+		s += c.unit().String()
+	}
+	return bps.NewFromUnitString(s)
+}
+
+// Format returns the field of `b` in the column, which is empty if `b` is nil.
+func (c Column) Format(b *bps.BPS) string {
+	if b == nil {
+		return ""
+	}
+	prec := c.Prec
+	if prec == 0 && !c.Fixed {
+		prec = -1
+	}
+	field := b.AppendFloat(nil, c.unit(), prec)
+	if c.Symbol {
+		field = append(field, c.unit().String()...)
+	}
+	return string(field)
+}
+
+// ParseError is the error of a BPS field.
+type ParseError struct {
+	// Row is the 1-based record number including the header.
+	Row int
+	// Line is the 1-based line number where the field starts, which differs from Row if a field has a newline.
+	Line int
+	// Column is the 1-based column number.
+	Column int
+	// Name is the header of the column if any.
+	Name string
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	name := ""
+	if e.Name != "" {
+		name = fmt.Sprintf(" (%s)", e.Name)
+	}
+	return fmt.Sprintf("bpscsv: row %d, line %d, column %d%s: %v", e.Row, e.Line, e.Column, name, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// resolve returns the indices of the columns, finding the names in header.
+func resolve(columns []Column, header []string) ([]int, error) {
+	indices := make([]int, len(columns))
+	for i, c := range columns {
+		if c.Name == "" {
+			if c.Index < 0 {
+				return nil, fmt.Errorf("bpscsv: negative index %d", c.Index)
+			}
+			indices[i] = c.Index
+			continue
+		}
+		indices[i] = -1
+		for j, h := range header {
+			if strings.TrimSpace(h) == c.Name {
+				indices[i] = j
+				break
+			}
+		}
+		if indices[i] < 0 {
+			return nil, fmt.Errorf("bpscsv: no column %q in the header", c.Name)
+		}
+	}
+	return indices, nil
+}
+
+// Reader reads BPS columns from a CSV file.
+type Reader struct {
+	// CSV is the underlying reader, which can be configured like Comma before the first Read.
+	CSV *csv.Reader
+
+	columns []Column
+	header  []string
+	// hasHeader reports whether the first record is the header.
+	hasHeader bool
+	indices   []int
+	row       int
+}
+
+// NewReader returns a new Reader that reads the columns from r.
+// If header is true, the first record is read as the header, which is required to find the columns by Name.
+func NewReader(r io.Reader, header bool, columns ...Column) *Reader {
+	return &Reader{
+		CSV:       csv.NewReader(r),
+		columns:   columns,
+		hasHeader: header,
+	}
+}
+
+// Header returns the header, which is read by the first Read.
+func (r *Reader) Header() []string {
+	return r.header
+}
+
+// init reads the header if necessary and resolves the columns.
+func (r *Reader) init() error {
+	if r.indices != nil {
+		return nil
+	}
+	if r.hasHeader {
+		h, err := r.CSV.Read()
+		if err != nil {
+			return err
+		}
+		r.row++
+		r.header = append([]string{}, h...)
+	}
+	indices, err := resolve(r.columns, r.header)
+	if err != nil {
+		return err
+	}
+	r.indices = indices
+	return nil
+}
+
+// Read reads one record and returns it with the values of the columns in the order of them.
+// A missing or empty field is nil. It returns io.EOF at the end of the input,
+// and a *ParseError if a field can't be parsed.
+func (r *Reader) Read() (record []string, values []*bps.BPS, err error) {
+	if err := r.init(); err != nil {
+		return nil, nil, err
+	}
+	record, err = r.CSV.Read()
+	if err != nil {
+		return nil, nil, err
+	}
+	r.row++
+
+	values = make([]*bps.BPS, len(r.columns))
+	for i, c := range r.columns {
+		idx := r.indices[i]
+		if idx >= len(record) {
+			continue
+		}
+		b, err := c.Parse(record[idx])
+		if err != nil {
+			line, _ := r.CSV.FieldPos(idx)
+			return record, nil, &ParseError{Row: r.row, Line: line, Column: idx + 1, Name: r.name(idx), Err: err}
+		}
+		values[i] = b
+	}
+	return record, values, nil
+}
+
+// name returns the header of the column idx if any.
+func (r *Reader) name(idx int) string {
+	if idx < len(r.header) {
+		return r.header[idx]
+	}
+	return ""
+}
+
+// Writer writes BPS columns to a CSV file.
+type Writer struct {
+	// CSV is the underlying writer, which can be configured like Comma before the first Write.
+	CSV *csv.Writer
+
+	columns []Column
+	indices []int
+}
+
+// NewWriter returns a new Writer that writes the columns to w.
+func NewWriter(w io.Writer, columns ...Column) *Writer {
+	return &Writer{
+		CSV:     csv.NewWriter(w),
+		columns: columns,
+	}
+}
+
+// WriteHeader writes the header and finds the columns by Name in it.
+// It must be called before Write if any column has Name.
+func (w *Writer) WriteHeader(header []string) error {
+	indices, err := resolve(w.columns, header)
+	if err != nil {
+		return err
+	}
+	w.indices = indices
+	return w.CSV.Write(header)
+}
+
+// Write writes a record after setting the formatted values to the fields of the columns.
+// values are in the order of the columns, and the record is extended if it's shorter than a column.
+// record itself is not modified.
+func (w *Writer) Write(record []string, values ...*bps.BPS) error {
+	if len(values) != len(w.columns) {
+		return fmt.Errorf("bpscsv: %d values for %d columns", len(values), len(w.columns))
+	}
+	if w.indices == nil {
+		indices, err := resolve(w.columns, nil)
+		if err != nil {
+			return err
+		}
+		w.indices = indices
+	}
+
+	out := append([]string{}, record...)
+	for i, c := range w.columns {
+		idx := w.indices[i]
+		for len(out) <= idx {
+			out = append(out, "")
+		}
+		out[idx] = c.Format(values[i])
+	}
+	return w.CSV.Write(out)
+}
+
+// Flush writes any buffered data to the underlying io.Writer, and returns the error if any.
+func (w *Writer) Flush() error {
+	w.CSV.Flush()
+	return w.CSV.Error()
+}
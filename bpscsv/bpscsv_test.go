@@ -0,0 +1,284 @@
+package bpscsv_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.mercari.io/go-bps/bps"
+	"go.mercari.io/go-bps/bpscsv"
+)
+
+func TestColumn_Parse(t *testing.T) {
+	tests := map[string]struct {
+		column  bpscsv.Column
+		field   string
+		want    *bps.BPS
+		wantErr bool
+	}{
+		"amount by default":                     {bpscsv.Column{}, "0.02645", bps.NewFromDeciBasisPoint(2645), false},
+		"percentage column":                     {bpscsv.Column{Unit: bps.Percentage}, " 2.645 ", bps.NewFromDeciBasisPoint(2645), false},
+		"basis point column":                    {bpscsv.Column{Unit: bps.BasisPoint}, "-264.5", bps.NewFromDeciBasisPoint(-2645), false},
+		"trailing decimal point":                {bpscsv.Column{Unit: bps.BasisPoint}, "3.", bps.NewFromBasisPoint(3), false},
+		"symbol overrides unit":                 {bpscsv.Column{Unit: bps.Percentage}, "264.5bp", bps.NewFromDeciBasisPoint(2645), false},
+		"empty is nil":                          {bpscsv.Column{Unit: bps.Percentage}, " ", nil, false},
+		"If invalid, it should return an error": {bpscsv.Column{Unit: bps.Percentage}, "2.6.45", nil, true},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tt.column.Parse(tt.field)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Column.Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Column.Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColumn_Format(t *testing.T) {
+	tests := map[string]struct {
+		column bpscsv.Column
+		b      *bps.BPS
+		want   string
+	}{
+		"amount by default":       {bpscsv.Column{}, bps.NewFromDeciBasisPoint(2645), "0.02645"},
+		"percentage with symbol":  {bpscsv.Column{Unit: bps.Percentage, Symbol: true}, bps.NewFromDeciBasisPoint(2645), "2.645%"},
+		"percentage with prec":    {bpscsv.Column{Unit: bps.Percentage, Prec: 2}, bps.NewFromDeciBasisPoint(2645), "2.65"},
+		"whole basis points":      {bpscsv.Column{Unit: bps.BasisPoint, Fixed: true}, bps.NewFromDeciBasisPoint(2645), "265"},
+		"whole negative amount":   {bpscsv.Column{Fixed: true}, bps.MustFromString("-396.5"), "-397"},
+		"fixed with prec":         {bpscsv.Column{Unit: bps.Percentage, Prec: 1, Fixed: true}, bps.NewFromPercentage(3), "3.0"},
+		"basis point with symbol": {bpscsv.Column{Unit: bps.BasisPoint, Symbol: true}, bps.NewFromBasisPoint(-3), "-3bp"},
+		"nil is empty":            {bpscsv.Column{Unit: bps.Percentage, Symbol: true}, nil, ""},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got := tt.column.Format(tt.b)
+			if got != tt.want {
+				t.Errorf("Column.Format() = %v, want %v", got, tt.want)
+			}
+			if tt.column.Prec == 0 && !tt.column.Fixed {
+				parsed, err := tt.column.Parse(got)
+				if err != nil || !reflect.DeepEqual(parsed, tt.b) {
+					t.Errorf("Column.Parse(%v) = %v, %v, want %v", got, parsed, err, tt.b)
+				}
+			}
+		})
+	}
+}
+
+func TestReader(t *testing.T) {
+	t.Parallel()
+
+	input := "merchant,fee,cap\n" +
+		"m1,2.645,10\n" +
+		"m2,150bp,\n" +
+		"m3,0.5\n"
+	r := bpscsv.NewReader(strings.NewReader(input), true,
+		bpscsv.Column{Name: "fee", Unit: bps.Percentage},
+		bpscsv.Column{Name: "cap", Unit: bps.Percentage},
+	)
+
+	want := []struct {
+		record []string
+		values []*bps.BPS
+	}{
+		{[]string{"m1", "2.645", "10"}, []*bps.BPS{bps.NewFromDeciBasisPoint(2645), bps.NewFromPercentage(10)}},
+		{[]string{"m2", "150bp", ""}, []*bps.BPS{bps.NewFromBasisPoint(150), nil}},
+		{[]string{"m3", "0.5"}, []*bps.BPS{bps.NewFromBasisPoint(50), nil}},
+	}
+	r.CSV.FieldsPerRecord = -1
+	for i, w := range want {
+		record, values, err := r.Read()
+		if err != nil {
+			t.Fatalf("Reader.Read() #%d error = %v", i, err)
+		}
+		if !reflect.DeepEqual(record, w.record) || !reflect.DeepEqual(values, w.values) {
+			t.Errorf("Reader.Read() #%d = %v, %v, want %v, %v", i, record, values, w.record, w.values)
+		}
+	}
+	if _, _, err := r.Read(); err != io.EOF {
+		t.Errorf("Reader.Read() error = %v, want io.EOF", err)
+	}
+	if got := r.Header(); !reflect.DeepEqual(got, []string{"merchant", "fee", "cap"}) {
+		t.Errorf("Reader.Header() = %v", got)
+	}
+}
+
+func TestReader_Error(t *testing.T) {
+	tests := map[string]struct {
+		input   string
+		header  bool
+		columns []bpscsv.Column
+		want    string
+	}{
+		"invalid field": {
+			"merchant,fee\nm1,1.5\n\"m\n2\",x\n",
+			true,
+			[]bpscsv.Column{{Name: "fee"}},
+			"bpscsv: row 3, line 4, column 2 (fee): can't convert x to BPS",
+		},
+		"invalid field without header": {
+			"m1,1.5\nm2,1.5%%\n",
+			false,
+			[]bpscsv.Column{{Index: 1}},
+			"bpscsv: row 2, line 2, column 2: can't convert 1.5%% to BPS",
+		},
+		"missing column": {
+			"merchant,rate\n",
+			true,
+			[]bpscsv.Column{{Name: "fee"}},
+			`bpscsv: no column "fee" in the header`,
+		},
+		"name without header": {
+			"m1,1.5\n",
+			false,
+			[]bpscsv.Column{{Name: "fee"}},
+			`bpscsv: no column "fee" in the header`,
+		},
+		"negative index": {
+			"m1,1.5\n",
+			false,
+			[]bpscsv.Column{{Index: -1}},
+			"bpscsv: negative index -1",
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			r := bpscsv.NewReader(strings.NewReader(tt.input), tt.header, tt.columns...)
+			var err error
+			for err == nil {
+				_, _, err = r.Read()
+			}
+			if err.Error() != tt.want {
+				t.Errorf("Reader.Read() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReader_ParseError(t *testing.T) {
+	t.Parallel()
+
+	r := bpscsv.NewReader(strings.NewReader("x\n"), false, bpscsv.Column{})
+	_, _, err := r.Read()
+	var pe *bpscsv.ParseError
+	if !errors.As(err, &pe) || pe.Row != 1 || pe.Column != 1 || pe.Err == nil {
+		t.Errorf("Reader.Read() error = %#v, want *ParseError", err)
+	}
+	if errors.Unwrap(err) != pe.Err {
+		t.Errorf("ParseError.Unwrap() = %v, want %v", errors.Unwrap(err), pe.Err)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w := bpscsv.NewWriter(&buf,
+		bpscsv.Column{Name: "fee", Unit: bps.Percentage, Symbol: true},
+		bpscsv.Column{Name: "cap", Unit: bps.BasisPoint, Prec: 1},
+	)
+	if err := w.WriteHeader([]string{"merchant", "fee", "cap"}); err != nil {
+		t.Fatal(err)
+	}
+	record := []string{"m1"}
+	if err := w.Write(record, bps.NewFromDeciBasisPoint(2645), bps.NewFromDeciBasisPoint(15)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write([]string{"m2", "old", "old", "note"}, nil, bps.NewFromBasisPoint(3)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write(record, nil); err == nil {
+		t.Error("Writer.Write() should return an error for the wrong number of values")
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "merchant,fee,cap\nm1,2.645%,1.5\nm2,,3.0,note\n"
+	if buf.String() != want {
+		t.Errorf("Writer wrote %q, want %q", buf.String(), want)
+	}
+	if !reflect.DeepEqual(record, []string{"m1"}) {
+		t.Errorf("Writer.Write() modified the record: %v", record)
+	}
+
+	if err := bpscsv.NewWriter(&buf, bpscsv.Column{Name: "fee"}).Write(nil, nil); err == nil {
+		t.Error("Writer.Write() should return an error if the header isn't written")
+	}
+}
+
+func TestReadWrite_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	columns := []bpscsv.Column{
+		{Index: 0, Unit: bps.Percentage},
+		{Index: 1, Unit: bps.DeciBasisPoint, Symbol: true},
+	}
+	values := [][]*bps.BPS{
+		{bps.NewFromDeciBasisPoint(2645), bps.NewFromDeciBasisPoint(-1)},
+		{nil, bps.MustFromString("0.000000001")},
+	}
+
+	var buf bytes.Buffer
+	w := bpscsv.NewWriter(&buf, columns...)
+	for _, v := range values {
+		if err := w.Write(nil, v...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := bpscsv.NewReader(&buf, false, columns...)
+	for _, v := range values {
+		_, got, err := r.Read()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, v) {
+			t.Errorf("Reader.Read() = %v, want %v", got, v)
+		}
+	}
+}
+
+func Example() {
+	input := "merchant,fee\nm1,2.645\nm2,150bp\n"
+	r := bpscsv.NewReader(strings.NewReader(input), true, bpscsv.Column{Name: "fee", Unit: bps.Percentage})
+
+	w := bpscsv.NewWriter(os.Stdout, bpscsv.Column{Name: "fee", Unit: bps.BasisPoint, Symbol: true})
+	for i := 0; ; i++ {
+		record, values, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if i == 0 {
+			w.WriteHeader(r.Header())
+		}
+		w.Write(record, values...)
+	}
+	w.Flush()
+	// Output:
+	// merchant,fee
+	// m1,264.5bp
+	// m2,150bp
+}
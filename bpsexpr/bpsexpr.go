@@ -0,0 +1,150 @@
+// Copyright © 2020 Merpay, Inc. All rights reserved.
+
+// Package bpsexpr provides a small expression language for formulas of BPS like fees:
+//
+//	max(3.6% * amount, 100) + 0.5% * amount
+//
+// An expression is evaluated exactly with the operations of the bps package, so it behaves like Go code using them:
+//
+//   - A number literal can have a unit symbol like 3.6%, 150bp or 2645dbp, and one without a symbol is an amount like 100.
+//   - A variable is an identifier like amount, which is given to Eval.
+//   - x + y and x - y are BPS.Add and BPS.Sub.
+//   - x * y is Rate.Mul, that means the product is rounded down to ppb.
+//   - x / y is BPS.Div if y is an integer amount, otherwise Amount.Div rounded to floor. Both round to ppb.
+//   - -x is BPS.Neg.
+//   - min(x, ...), max(x, ...) and abs(x) are bps.Min, bps.Max and BPS.Abs.
+//   - round(x, mode, unit) is BPS.RoundTo, where mode is one of down, up, floor, ceiling, half_up, half_down and half_even,
+//     and unit is one of ppb, ppm, dbp, hbp, bp, percent and amount.
+//   - The comparisons <, <=, >, >=, == and != compare numbers, and != and == also compare booleans.
+//   - The logical operators &&, || and ! are short-circuit.
+//   - cond ? x : y is a conditional, and only the chosen branch is evaluated.
+//
+// The operators have the same precedence as Go, and the comparisons are not associative.
+// Parse validates an expression statically: the syntax, the functions, the number of the arguments,
+// and the types, e.g. a condition must be a boolean and the result must be a number.
+package bpsexpr // import "go.mercari.io/go-bps/bpsexpr"
+
+import (
+	"fmt"
+	"sort"
+
+	"go.mercari.io/go-bps/bps"
+)
+
+// Expr is a parsed expression, which can be evaluated concurrently.
+type Expr struct {
+	src  string
+	root node
+	vars []string
+}
+
+// Parse parses and validates src, and returns the expression.
+// It returns a *SyntaxError if src is invalid.
+func Parse(src string) (*Expr, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks, vars: map[string]bool{}}
+	pos := p.peek().pos
+	root, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, unexpected(t, "an operator or end of expression")
+	}
+	if err := check(root, pos, kindNumber, "expression"); err != nil {
+		return nil, err
+	}
+
+	vars := make([]string, 0, len(p.vars))
+	for v := range p.vars {
+		vars = append(vars, v)
+	}
+	sort.Strings(vars)
+	return &Expr{src: src, root: root, vars: vars}, nil
+}
+
+// MustParse is like Parse but panics if src is invalid.
+// It simplifies safe initialization of global variables holding expressions.
+func MustParse(src string) *Expr {
+	e, err := Parse(src)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// Variables returns the names of the variables in the expression in sorted order.
+func (e *Expr) Variables() []string {
+	return append([]string{}, e.vars...)
+}
+
+// Validate returns an error if the expression uses a variable which is not in vars, e.g. a typo in a configuration.
+func (e *Expr) Validate(vars ...string) error {
+	known := make(map[string]bool, len(vars))
+	for _, v := range vars {
+		known[v] = true
+	}
+	for _, v := range e.vars {
+		if !known[v] {
+			return fmt.Errorf("bpsexpr: undefined variable %s", v)
+		}
+	}
+	return nil
+}
+
+// Eval evaluates the expression with the values of the variables.
+// It returns an *EvalError if a variable is missing or a division by zero occurs.
+// The result is a new BPS, so the caller may modify it without affecting `e` or the variables.
+func (e *Expr) Eval(vars map[string]*bps.BPS) (*bps.BPS, error) {
+	v, err := e.root.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	// the nodes may return a literal of `e` or a variable as is
+	return new(bps.BPS).Set(v.num), nil
+}
+
+// String returns the source of the expression.
+func (e *Expr) String() string {
+	return e.src
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (e *Expr) MarshalText() ([]byte, error) {
+	return []byte(e.src), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface, so an expression can be loaded from a configuration.
+func (e *Expr) UnmarshalText(text []byte) error {
+	n, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*e = *n
+	return nil
+}
+
+// SyntaxError is an error of Parse.
+type SyntaxError struct {
+	// Pos is the 0-based byte offset in the source.
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("bpsexpr: syntax error at %d: %s", e.Pos, e.Msg)
+}
+
+// EvalError is an error of Eval.
+type EvalError struct {
+	// Pos is the 0-based byte offset in the source.
+	Pos int
+	Msg string
+}
+
+func (e *EvalError) Error() string {
+	return fmt.Sprintf("bpsexpr: evaluation error at %d: %s", e.Pos, e.Msg)
+}
@@ -0,0 +1,275 @@
+package bpsexpr_test
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.mercari.io/go-bps/bps"
+	"go.mercari.io/go-bps/bpsexpr"
+)
+
+func TestExpr_Eval(t *testing.T) {
+	vars := map[string]*bps.BPS{
+		"amount": bps.NewFromAmount(14999),
+		"small":  bps.NewFromAmount(1000),
+		"rate":   bps.NewFromDeciBasisPoint(2645),
+	}
+	tests := map[string]struct {
+		src  string
+		want *bps.BPS
+	}{
+		"fee formula": {
+			"max(3.6% * amount, 100) + 0.5% * amount",
+			bps.NewFromAmount(14999).Mul(36).Div(1000).Add(bps.NewFromAmount(14999).Mul(5).Div(1000)),
+		},
+		"fee formula with the minimum": {
+			"max(3.6% * small, 100) + 0.5% * small",
+			bps.NewFromAmount(105),
+		},
+		"unit symbols": {
+			"1% + 150bp + 2645dbp + 3hbp + 5ppm + 7ppb",
+			bps.NewFromPercentage(1).Add(bps.NewFromBasisPoint(150)).Add(bps.NewFromDeciBasisPoint(2645)).
+				Add(bps.NewFromHalfBasisPoint(3)).Add(bps.NewFromPPM(big.NewInt(5))).Add(bps.NewFromPPB(big.NewInt(7))),
+		},
+		"precedence": {
+			"1 + 2 * 3 - 4 / 2",
+			bps.NewFromAmount(5),
+		},
+		"parentheses and unary minus": {
+			"-(1 + 2) * -3",
+			bps.NewFromAmount(9),
+		},
+		"multiplication is rounded down to ppb": {
+			"0.000000001 * 0.5",
+			bps.NewFromAmount(0),
+		},
+		"negative multiplication is rounded to floor like Rate.Mul": {
+			"-0.000000001 * 0.5",
+			bps.NewFromPPB(big.NewInt(-1)),
+		},
+		"division by an integer is BPS.Div": {
+			"rate / 3",
+			bps.NewFromDeciBasisPoint(2645).Div(3),
+		},
+		"division by a negative integer is BPS.Div": {
+			"-0.000000001 / -2",
+			bps.NewFromPPB(big.NewInt(-1)).Div(-2),
+		},
+		"division by a decimal is rounded to floor": {
+			"1 / 0.3",
+			bps.MustFromString("3.333333333"),
+		},
+		"round": {
+			"round(rate * amount, half_up, amount)",
+			bps.NewFromAmount(397),
+		},
+		"round to percent": {
+			"round(rate, half_even, percent)",
+			bps.NewFromPercentage(3),
+		},
+		"round to bp": {
+			"round(-1.5bp, down, bp)",
+			bps.NewFromBasisPoint(-1),
+		},
+		"min and abs": {
+			"min(abs(-5), 3, 4)",
+			bps.NewFromAmount(3),
+		},
+		"conditional": {
+			"amount >= 10000 ? 1% * amount : 100",
+			bps.MustFromString("149.99"),
+		},
+		"nested conditional": {
+			"amount < 1000 ? 0 : amount < 10000 ? 1 : 2",
+			bps.NewFromAmount(2),
+		},
+		"logical operators": {
+			"(amount > 0 && !(rate == 0)) || small != 1000 ? 1 : 0",
+			bps.NewFromAmount(1),
+		},
+		"boolean equality": {
+			"(amount > 0) == (rate > 0) ? 1 : 0",
+			bps.NewFromAmount(1),
+		},
+		"short circuit avoids division by zero": {
+			"small == 1000 || 1 / 0 > 0 ? 1 / (small - 1000 + 1) : 1 / 0",
+			bps.NewFromAmount(1),
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			e, err := bpsexpr.Parse(tt.src)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			got, err := e.Eval(vars)
+			if err != nil {
+				t.Fatalf("Expr.Eval() error = %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Expr.Eval() = %v, want %v", got.FloatString(9), tt.want.FloatString(9))
+			}
+		})
+	}
+}
+
+func TestExpr_Eval_NewResult(t *testing.T) {
+	tests := map[string]struct {
+		src  string
+		want *bps.BPS
+	}{
+		"literal":          {"100", bps.NewFromAmount(100)},
+		"max of a literal": {"max(100, amount)", bps.NewFromAmount(100)},
+		"variable":         {"amount", bps.NewFromAmount(50)},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			e := bpsexpr.MustParse(tt.src)
+			amount := bps.NewFromAmount(50)
+			vars := map[string]*bps.BPS{"amount": amount}
+			got, err := e.Eval(vars)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got.SetAdd(got, bps.NewFromAmount(1))
+
+			if got, err := e.Eval(vars); err != nil || !got.Equal(tt.want) {
+				t.Errorf("Expr.Eval() after modifying the result = %v, %v, want %v", got, err, tt.want)
+			}
+			if !amount.Equal(bps.NewFromAmount(50)) {
+				t.Errorf("the variable is modified to %v", amount)
+			}
+		})
+	}
+}
+
+func TestExpr_EvalError(t *testing.T) {
+	tests := map[string]struct {
+		src  string
+		want string
+	}{
+		"undefined variable": {"1 + amount", "bpsexpr: evaluation error at 4: undefined variable amount"},
+		"division by zero":   {"1 / (2 - 2)", "bpsexpr: evaluation error at 2: division by zero"},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			_, err := bpsexpr.MustParse(tt.src).Eval(nil)
+			var ee *bpsexpr.EvalError
+			if !errors.As(err, &ee) || err.Error() != tt.want {
+				t.Errorf("Expr.Eval() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_Error(t *testing.T) {
+	tests := map[string]struct {
+		src  string
+		want string
+	}{
+		"empty":                     {"", "at 0: unexpected end of expression"},
+		"unexpected character":      {"1 $ 2", `at 2: unexpected character '$'`},
+		"unknown unit":              {"3bips", `at 1: unknown unit "bips"`},
+		"invalid number":            {"1.2.3", `at 0: invalid number "1.2.3"`},
+		"missing operand":           {"1 +", "at 3: unexpected end of expression"},
+		"missing parenthesis":       {"(1 + 2", `at 6: unexpected end of expression, want ")"`},
+		"trailing token":            {"1 2", `at 2: unexpected "2", want an operator`},
+		"unknown function":          {"avg(1, 2)", "at 0: unknown function avg"},
+		"function without call":     {"max + 1", "at 0: function max must be called"},
+		"wrong number of arguments": {"abs(1, 2)", "at 0: wrong number of arguments for abs: 2"},
+		"no arguments":              {"min()", "at 0: wrong number of arguments for min: 0"},
+		"invalid rounding mode":     {"round(1, nearest, bp)", `at 9: unexpected "nearest", want a rounding mode`},
+		"invalid unit":              {"round(1, up, 1)", `at 13: unexpected "1", want a unit`},
+		"boolean result":            {"1 < 2", "at 0: expression must be number, got boolean"},
+		"number condition":          {"1 ? 2 : 3", "at 0: condition must be boolean, got number"},
+		"mismatched branches":       {"1 < 2 ? 1 : 1 < 2", "at 6: branches must have the same type"},
+		"boolean arithmetic":        {"(1 < 2) + 1", "at 8: operand of + must be number, got boolean"},
+		"number logic":              {"1 && 2 < 3 ? 1 : 0", "at 2: operand of && must be boolean, got number"},
+		"mismatched equality":       {"1 == (1 < 2) ? 1 : 0", "at 2: mismatched types number and boolean"},
+		"chained comparison":        {"1 < 2 < 3 ? 1 : 0", `at 6: unexpected "<", want end of comparison`},
+		"not a number":              {"!1", "at 0: operand of ! must be boolean, got number"},
+		"negative boolean":          {"-(1 < 2) ? 1 : 0", "at 0: operand of - must be number, got boolean"},
+		"boolean argument":          {"max(1, 1 < 2)", "at 7: argument of max must be number, got boolean"},
+		"boolean argument of round": {"round(1 < 2, up, bp)", "at 6: argument of round must be number, got boolean"},
+		"missing colon":             {"1 < 2 ? 1", `at 9: unexpected end of expression, want ":"`},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			_, err := bpsexpr.Parse(tt.src)
+			var se *bpsexpr.SyntaxError
+			if !errors.As(err, &se) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpr_Variables(t *testing.T) {
+	t.Parallel()
+
+	e := bpsexpr.MustParse("round(max(rate * amount, min_fee), half_up, amount) + rate")
+	if got, want := e.Variables(), []string{"amount", "min_fee", "rate"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expr.Variables() = %v, want %v", got, want)
+	}
+	if err := e.Validate("amount", "rate", "min_fee", "unused"); err != nil {
+		t.Errorf("Expr.Validate() error = %v", err)
+	}
+	if err := e.Validate("amount", "rate"); err == nil || err.Error() != "bpsexpr: undefined variable min_fee" {
+		t.Errorf("Expr.Validate() error = %v", err)
+	}
+}
+
+func TestExpr_Text(t *testing.T) {
+	t.Parallel()
+
+	var e bpsexpr.Expr
+	if err := e.UnmarshalText([]byte("1% * amount")); err != nil {
+		t.Fatal(err)
+	}
+	text, _ := e.MarshalText()
+	if string(text) != "1% * amount" || e.String() != "1% * amount" {
+		t.Errorf("Expr.MarshalText() = %s", text)
+	}
+	if err := e.UnmarshalText([]byte("1% *")); err == nil {
+		t.Error("Expr.UnmarshalText() should return an error")
+	}
+}
+
+func TestMustParse(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MustParse() should panic")
+		}
+	}()
+	bpsexpr.MustParse("1 +")
+}
+
+func Example() {
+	fee := bpsexpr.MustParse("max(3.6% * amount, 100) + 0.5% * amount")
+
+	for _, amount := range []int64{1000, 14999} {
+		v, err := fee.Eval(map[string]*bps.BPS{"amount": bps.NewFromAmount(amount)})
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Println(v.FloatString(3))
+	}
+	// Output:
+	// 105.000
+	// 614.959
+}
@@ -0,0 +1,135 @@
+package bpsexpr
+
+import (
+	"fmt"
+
+	"go.mercari.io/go-bps/bps"
+)
+
+// value is the result of a node, which is num for kindNumber and b for kindBool.
+type value struct {
+	num *bps.BPS
+	b   bool
+}
+
+func (n numberNode) eval(map[string]*bps.BPS) (value, error) {
+	return value{num: n.v}, nil
+}
+
+func (n varNode) eval(vars map[string]*bps.BPS) (value, error) {
+	v, ok := vars[n.name]
+	if !ok || v == nil {
+		return value{}, &EvalError{Pos: n.pos, Msg: fmt.Sprintf("undefined variable %s", n.name)}
+	}
+	return value{num: v}, nil
+}
+
+func (n unaryNode) eval(vars map[string]*bps.BPS) (value, error) {
+	x, err := n.x.eval(vars)
+	if err != nil {
+		return value{}, err
+	}
+	if n.op == "!" {
+		return value{b: !x.b}, nil
+	}
+	return value{num: x.num.Neg()}, nil
+}
+
+func (n binaryNode) eval(vars map[string]*bps.BPS) (value, error) {
+	x, err := n.x.eval(vars)
+	if err != nil {
+		return value{}, err
+	}
+	// short-circuit evaluation
+	switch {
+	case n.op == "&&" && !x.b:
+		return value{b: false}, nil
+	case n.op == "||" && x.b:
+		return value{b: true}, nil
+	}
+	y, err := n.y.eval(vars)
+	if err != nil {
+		return value{}, err
+	}
+
+	switch n.op {
+	case "&&", "||":
+		return value{b: y.b}, nil
+	case "+":
+		return value{num: x.num.Add(y.num)}, nil
+	case "-":
+		return value{num: x.num.Sub(y.num)}, nil
+	case "*":
+		return value{num: bps.RateFromBPS(x.num).Mul(bps.AmountFromBPS(y.num)).BPS()}, nil
+	case "/":
+		return n.div(x.num, y.num)
+	case "==", "!=":
+		eq := x.b == y.b
+		if n.x.kind() == kindNumber {
+			eq = x.num.Equal(y.num)
+		}
+		return value{b: eq == (n.op == "==")}, nil
+	}
+
+	c := x.num.Cmp(y.num)
+	switch n.op {
+	case "<":
+		return value{b: c < 0}, nil
+	case "<=":
+		return value{b: c <= 0}, nil
+	case ">":
+		return value{b: c > 0}, nil
+	}
+	// ">="
+	return value{b: c >= 0}, nil
+}
+
+// div returns x / y like BPS.Div if y is an integer amount, otherwise like Amount.Div rounded to floor.
+func (n binaryNode) div(x, y *bps.BPS) (value, error) {
+	if y.IsZero() {
+		return value{}, &EvalError{Pos: n.pos, Msg: "division by zero"}
+	}
+	if y.IsMultipleOf(bps.Unity) {
+		if i, err := y.AmountsInt64(); err == nil {
+			return value{num: x.Div(i)}, nil
+		}
+	}
+	r, err := bps.AmountFromBPS(x).Div(bps.AmountFromBPS(y), bps.RoundFloor)
+	if err != nil {
+		return value{}, &EvalError{Pos: n.pos, Msg: err.Error()}
+	}
+	return value{num: r.BPS()}, nil
+}
+
+func (n condNode) eval(vars map[string]*bps.BPS) (value, error) {
+	c, err := n.cond.eval(vars)
+	if err != nil {
+		return value{}, err
+	}
+	if c.b {
+		return n.then.eval(vars)
+	}
+	return n.els.eval(vars)
+}
+
+func (n callNode) eval(vars map[string]*bps.BPS) (value, error) {
+	args := make([]*bps.BPS, len(n.args))
+	for i, a := range n.args {
+		v, err := a.eval(vars)
+		if err != nil {
+			return value{}, err
+		}
+		args[i] = v.num
+	}
+
+	switch n.name {
+	case "min":
+		return value{num: bps.Min(args[0], args[1:]...)}, nil
+	case "max":
+		return value{num: bps.Max(args[0], args[1:]...)}, nil
+	case "abs":
+		return value{num: args[0].Abs()}, nil
+	}
+	// round
+	return value{num: args[0].RoundTo(n.unit, n.mode)}, nil
+}
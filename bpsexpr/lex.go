@@ -0,0 +1,103 @@
+package bpsexpr
+
+import (
+	"fmt"
+	"strings"
+
+	"go.mercari.io/go-bps/bps"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokOp // operators and punctuations
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+	// num is the value of tokNumber.
+	num *bps.BPS
+}
+
+// operators are the operators and punctuations, longer ones first.
+var operators = []string{"<=", ">=", "==", "!=", "&&", "||", "+", "-", "*", "/", "<", ">", "!", "?", ":", "(", ")", ","}
+
+// lex splits src into tokens.
+func lex(src string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		case isDigit(c) || c == '.':
+			t, err := lexNumber(src, i)
+			if err != nil {
+				return nil, err
+			}
+			toks = append(toks, t)
+			i += len(t.text)
+		case isLetter(c):
+			j := i + 1
+			for j < len(src) && (isLetter(src[j]) || isDigit(src[j])) {
+				j++
+			}
+			toks = append(toks, token{kind: tokIdent, text: src[i:j], pos: i})
+			i = j
+		default:
+			op := ""
+			for _, o := range operators {
+				if strings.HasPrefix(src[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, &SyntaxError{Pos: i, Msg: fmt.Sprintf("unexpected character %q", c)}
+			}
+			toks = append(toks, token{kind: tokOp, text: op, pos: i})
+			i += len(op)
+		}
+	}
+	return append(toks, token{kind: tokEOF, pos: len(src)}), nil
+}
+
+// lexNumber reads a number literal with an optional unit symbol like "3.6%" or "150bp" at src[i:].
+func lexNumber(src string, i int) (token, error) {
+	j := i
+	for j < len(src) && (isDigit(src[j]) || src[j] == '.') {
+		j++
+	}
+	switch {
+	case j < len(src) && src[j] == '%':
+		j++
+	case j < len(src) && isLetter(src[j]):
+		k := j
+		for k < len(src) && (isLetter(src[k]) || isDigit(src[k])) {
+			k++
+		}
+		if _, err := bps.ParseUnit(src[j:k]); err != nil {
+			return token{}, &SyntaxError{Pos: j, Msg: fmt.Sprintf("unknown unit %q", src[j:k])}
+		}
+		j = k
+	}
+	text := src[i:j]
+	b, err := bps.NewFromUnitString(text)
+	if err != nil {
+		return token{}, &SyntaxError{Pos: i, Msg: fmt.Sprintf("invalid number %q", text)}
+	}
+	return token{kind: tokNumber, text: text, pos: i, num: b}, nil
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || c == '_'
+}
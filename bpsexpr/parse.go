@@ -0,0 +1,378 @@
+package bpsexpr
+
+import (
+	"fmt"
+
+	"go.mercari.io/go-bps/bps"
+)
+
+// kind is the static type of a node.
+type kind int
+
+const (
+	kindNumber kind = iota
+	kindBool
+)
+
+func (k kind) String() string {
+	if k == kindBool {
+		return "boolean"
+	}
+	return "number"
+}
+
+type node interface {
+	kind() kind
+	eval(vars map[string]*bps.BPS) (value, error)
+}
+
+type (
+	numberNode struct {
+		v *bps.BPS
+	}
+	varNode struct {
+		pos  int
+		name string
+	}
+	unaryNode struct {
+		op string
+		x  node
+	}
+	binaryNode struct {
+		pos  int
+		op   string
+		x, y node
+	}
+	condNode struct {
+		cond, then, els node
+	}
+	callNode struct {
+		name string
+		args []node
+		// mode and unit are the arguments of round.
+		mode bps.RoundingMode
+		unit bps.Unit
+	}
+)
+
+func (numberNode) kind() kind { return kindNumber }
+func (varNode) kind() kind    { return kindNumber }
+func (n unaryNode) kind() kind {
+	if n.op == "!" {
+		return kindBool
+	}
+	return kindNumber
+}
+func (n binaryNode) kind() kind {
+	switch n.op {
+	case "+", "-", "*", "/":
+		return kindNumber
+	}
+	return kindBool
+}
+func (n condNode) kind() kind { return n.then.kind() }
+func (callNode) kind() kind   { return kindNumber }
+
+// functions are the arities of the functions, and -1 means variadic with at least one argument.
+var functions = map[string]int{
+	"min":   -1,
+	"max":   -1,
+	"abs":   1,
+	"round": 3,
+}
+
+var roundingModes = map[string]bps.RoundingMode{
+	"down":      bps.RoundDown,
+	"up":        bps.RoundUp,
+	"floor":     bps.RoundFloor,
+	"ceiling":   bps.RoundCeiling,
+	"half_up":   bps.RoundHalfUp,
+	"half_down": bps.RoundHalfDown,
+	"half_even": bps.RoundHalfEven,
+}
+
+type parser struct {
+	toks []token
+	i    int
+	vars map[string]bool
+}
+
+// peek returns the current token.
+func (p *parser) peek() token {
+	return p.toks[p.i]
+}
+
+// next returns the current token and advances.
+func (p *parser) next() token {
+	t := p.toks[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+// accept advances and returns true if the current token is one of the operators.
+func (p *parser) accept(ops ...string) (token, bool) {
+	t := p.peek()
+	if t.kind != tokOp {
+		return t, false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			p.i++
+			return t, true
+		}
+	}
+	return t, false
+}
+
+func (p *parser) expect(op string) error {
+	if t, ok := p.accept(op); !ok {
+		return unexpected(t, fmt.Sprintf("%q", op))
+	}
+	return nil
+}
+
+func unexpected(t token, want string) error {
+	if t.kind == tokEOF {
+		return &SyntaxError{Pos: t.pos, Msg: "unexpected end of expression, want " + want}
+	}
+	return &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("unexpected %q, want %s", t.text, want)}
+}
+
+// check returns an error if n is not the kind k.
+func check(n node, pos int, k kind, what string) error {
+	if n.kind() != k {
+		return &SyntaxError{Pos: pos, Msg: fmt.Sprintf("%s must be %s, got %s", what, k, n.kind())}
+	}
+	return nil
+}
+
+// parseExpr parses: or ('?' expr ':' expr)?
+func (p *parser) parseExpr() (node, error) {
+	pos := p.peek().pos
+	cond, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	t, ok := p.accept("?")
+	if !ok {
+		return cond, nil
+	}
+	if err := check(cond, pos, kindBool, "condition"); err != nil {
+		return nil, err
+	}
+	then, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	els, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if then.kind() != els.kind() {
+		return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("branches must have the same type, got %s and %s", then.kind(), els.kind())}
+	}
+	return condNode{cond: cond, then: then, els: els}, nil
+}
+
+// precedences are the binary operators from the lowest precedence.
+var precedences = [][]string{
+	{"||"},
+	{"&&"},
+	{"<", "<=", ">", ">=", "==", "!="},
+	{"+", "-"},
+	{"*", "/"},
+}
+
+// parseBinary parses the binary operators of the precedence level and higher.
+func (p *parser) parseBinary(level int) (node, error) {
+	if level == len(precedences) {
+		return p.parseUnary()
+	}
+	x, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.accept(precedences[level]...)
+		if !ok {
+			return x, nil
+		}
+		y, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		n := binaryNode{pos: t.pos, op: t.text, x: x, y: y}
+		switch t.text {
+		case "&&", "||":
+			if err := check(x, t.pos, kindBool, "operand of "+t.text); err != nil {
+				return nil, err
+			}
+			if err := check(y, t.pos, kindBool, "operand of "+t.text); err != nil {
+				return nil, err
+			}
+		case "==", "!=":
+			if x.kind() != y.kind() {
+				return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("mismatched types %s and %s", x.kind(), y.kind())}
+			}
+		default:
+			if err := check(x, t.pos, kindNumber, "operand of "+t.text); err != nil {
+				return nil, err
+			}
+			if err := check(y, t.pos, kindNumber, "operand of "+t.text); err != nil {
+				return nil, err
+			}
+		}
+		if level == 2 {
+			// comparisons are not associative, e.g. "a < b < c" is an error
+			if t, ok := p.accept(precedences[level]...); ok {
+				return nil, unexpected(t, "end of comparison")
+			}
+			return n, nil
+		}
+		x = n
+	}
+}
+
+// parseUnary parses: ('-' | '!') unary | primary
+func (p *parser) parseUnary() (node, error) {
+	t, ok := p.accept("-", "!")
+	if !ok {
+		return p.parsePrimary()
+	}
+	x, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	k := kindNumber
+	if t.text == "!" {
+		k = kindBool
+	}
+	if err := check(x, t.pos, k, "operand of "+t.text); err != nil {
+		return nil, err
+	}
+	return unaryNode{op: t.text, x: x}, nil
+}
+
+// parsePrimary parses: number | ident | ident '(' args ')' | '(' expr ')'
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		return numberNode{v: t.num}, nil
+	case tokIdent:
+		if _, ok := p.accept("("); ok {
+			return p.parseCall(t)
+		}
+		if _, ok := functions[t.text]; ok {
+			return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("function %s must be called", t.text)}
+		}
+		p.vars[t.text] = true
+		return varNode{pos: t.pos, name: t.text}, nil
+	case tokOp:
+		if t.text == "(" {
+			x, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return x, nil
+		}
+	}
+	return nil, unexpected(t, "a number, a variable or \"(\"")
+}
+
+// parseCall parses the arguments of the function call after "(".
+func (p *parser) parseCall(fn token) (node, error) {
+	arity, ok := functions[fn.text]
+	if !ok {
+		return nil, &SyntaxError{Pos: fn.pos, Msg: fmt.Sprintf("unknown function %s", fn.text)}
+	}
+	n := callNode{name: fn.text}
+	if fn.text == "round" {
+		return p.parseRound(n)
+	}
+
+	if _, ok := p.accept(")"); !ok {
+		for {
+			pos := p.peek().pos
+			x, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err := check(x, pos, kindNumber, "argument of "+fn.text); err != nil {
+				return nil, err
+			}
+			n.args = append(n.args, x)
+			if _, ok := p.accept(","); !ok {
+				break
+			}
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	}
+	if (arity < 0 && len(n.args) == 0) || (arity >= 0 && len(n.args) != arity) {
+		return nil, &SyntaxError{Pos: fn.pos, Msg: fmt.Sprintf("wrong number of arguments for %s: %d", fn.text, len(n.args))}
+	}
+	return n, nil
+}
+
+// parseRound parses the arguments of round(x, mode, unit) after "(".
+func (p *parser) parseRound(n callNode) (node, error) {
+	pos := p.peek().pos
+	x, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if err := check(x, pos, kindNumber, "argument of round"); err != nil {
+		return nil, err
+	}
+	n.args = []node{x}
+
+	if err := p.expect(","); err != nil {
+		return nil, err
+	}
+	t := p.next()
+	mode, ok := roundingModes[t.text]
+	if t.kind != tokIdent || !ok {
+		return nil, unexpected(t, "a rounding mode like half_up")
+	}
+	n.mode = mode
+
+	if err := p.expect(","); err != nil {
+		return nil, err
+	}
+	t = p.next()
+	unit, ok := parseUnit(t)
+	if !ok {
+		return nil, unexpected(t, "a unit like bp")
+	}
+	n.unit = unit
+
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// parseUnit returns the unit of a symbol accepted by bps.ParseUnit, "percent" or "amount".
+func parseUnit(t token) (bps.Unit, bool) {
+	if t.kind != tokIdent {
+		return 0, false
+	}
+	switch t.text {
+	case "percent":
+		return bps.Percentage, true
+	case "amount":
+		return bps.Unity, true
+	}
+	u, err := bps.ParseUnit(t.text)
+	return u, err == nil
+}
@@ -0,0 +1,118 @@
+// Copyright © 2020 Merpay, Inc. All rights reserved.
+
+// Package bpsgraphql provides the GraphQL scalar of BPS.
+//
+// BPS and NullBPS implement the MarshalGQL/UnmarshalGQL contract of github.com/99designs/gqlgen,
+// so they can be bound to the scalar in Schema by gqlgen.yml:
+//
+//	models:
+//	  BPS:
+//	    model: go.mercari.io/go-bps/bpsgraphql.BPS
+//
+// A value is output as a string of the shortest exact decimal amount like "0.02645" to keep the precision,
+// since a JSON number is usually decoded as float64.
+//
+// The input is accepted as:
+//
+//   - string: parsed by bps.NewFromUnitString, e.g. "0.02645", "2.645%" or "264.5bp". It's exact.
+//   - integer: an integer amount, e.g. 1 means 100%. It's exact.
+//   - json.Number: parsed exactly including an exponent like "2.5e-2", and rounded half even to ppb like a float.
+//   - float: converted by bps.NewFromFloat64 via its shortest decimal representation, and rounded half even to ppb.
+//
+// Clients should send a string for the exact value.
+package bpsgraphql // import "go.mercari.io/go-bps/bpsgraphql"
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+
+	"go.mercari.io/go-bps/bps"
+)
+
+// Schema is the GraphQL schema snippet that defines the BPS scalar.
+//
+//go:embed schema.graphql
+var Schema string
+
+// BPS is bps.BPS which implements the GraphQL scalar.
+// Convert *bps.BPS like (*bpsgraphql.BPS)(b), which refers to the same value.
+type BPS bps.BPS
+
+// MarshalGQL writes `b` as a JSON string like "0.02645".
+func (b BPS) MarshalGQL(w io.Writer) {
+	v := bps.BPS(b)
+	// AppendText of BPS never fails
+	text, _ := v.AppendText([]byte{'"'})
+	w.Write(append(text, '"'))
+}
+
+// UnmarshalGQL sets `b` to the input value v.
+func (b *BPS) UnmarshalGQL(v interface{}) error {
+	res, err := unmarshal(v)
+	if err != nil {
+		return err
+	}
+	*b = BPS(*res)
+	return nil
+}
+
+// NullBPS is bps.NullBPS which implements the nullable GraphQL scalar.
+type NullBPS bps.NullBPS
+
+// MarshalGQL writes `n` as a JSON string like BPS, or null if `n` is not valid.
+func (n NullBPS) MarshalGQL(w io.Writer) {
+	if !n.Valid {
+		io.WriteString(w, "null")
+		return
+	}
+	BPS(n.BPS).MarshalGQL(w)
+}
+
+// UnmarshalGQL sets `n` to the input value v, which is not valid if v is nil.
+func (n *NullBPS) UnmarshalGQL(v interface{}) error {
+	if v == nil {
+		*n = NullBPS{}
+		return nil
+	}
+	res, err := unmarshal(v)
+	if err != nil {
+		return err
+	}
+	*n = NullBPS{BPS: *res, Valid: true}
+	return nil
+}
+
+// unmarshal returns a new BPS from the input value v by the rules of the package document.
+func unmarshal(v interface{}) (*bps.BPS, error) {
+	switch v := v.(type) {
+	case string:
+		return bps.NewFromUnitString(v)
+	case json.Number:
+		// big.Rat also accepts a fraction like "1/3", which isn't a JSON number
+		r, ok := new(big.Rat).SetString(string(v))
+		if !ok || !json.Valid([]byte(v)) {
+			return nil, fmt.Errorf("can't convert %s to BPS: not a JSON number", v)
+		}
+		return bps.NewFromRat(r, bps.RoundHalfEven), nil
+	case int:
+		return bps.NewFromAmount(int64(v)), nil
+	case int32:
+		return bps.NewFromAmount(int64(v)), nil
+	case int64:
+		return bps.NewFromAmount(v), nil
+	case float32:
+		// the shortest representation of float32, e.g. 0.1 instead of 0.10000000149011612
+		s, err := bps.ParseScaled(strconv.FormatFloat(float64(v), 'f', -1, 32), 9, bps.RoundHalfEven)
+		if err != nil {
+			return nil, err
+		}
+		return s.BPS(bps.RoundHalfEven), nil
+	case float64:
+		return bps.NewFromFloat64(v, bps.RoundHalfEven)
+	}
+	return nil, fmt.Errorf("BPS must be a string or a number, got %T", v)
+}
@@ -0,0 +1,145 @@
+package bpsgraphql_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.mercari.io/go-bps/bps"
+	"go.mercari.io/go-bps/bpsgraphql"
+)
+
+func TestBPS_MarshalGQL(t *testing.T) {
+	tests := map[string]struct {
+		b    *bps.BPS
+		want string
+	}{
+		"rate":     {bps.NewFromDeciBasisPoint(2645), `"0.02645"`},
+		"negative": {bps.NewFromPPB(big.NewInt(-1)), `"-0.000000001"`},
+		"zero":     {&bps.BPS{}, `"0"`},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var buf bytes.Buffer
+			(*bpsgraphql.BPS)(tt.b).MarshalGQL(&buf)
+			if got := buf.String(); got != tt.want {
+				t.Errorf("BPS.MarshalGQL() = %v, want %v", got, tt.want)
+			}
+			var s string
+			if err := json.Unmarshal(buf.Bytes(), &s); err != nil {
+				t.Errorf("BPS.MarshalGQL() should write a JSON string: %v", err)
+			}
+		})
+	}
+}
+
+func TestBPS_UnmarshalGQL(t *testing.T) {
+	tests := map[string]struct {
+		v       interface{}
+		want    *bps.BPS
+		wantErr bool
+	}{
+		"decimal string":                               {"0.02645", bps.NewFromDeciBasisPoint(2645), false},
+		"percent string":                               {"2.645%", bps.NewFromDeciBasisPoint(2645), false},
+		"basis point string":                           {"264.5bp", bps.NewFromDeciBasisPoint(2645), false},
+		"int is an amount":                             {1, bps.NewFromPercentage(100), false},
+		"int64 is an amount":                           {int64(-2), bps.NewFromAmount(-2), false},
+		"json.Number":                                  {json.Number("0.02645"), bps.NewFromDeciBasisPoint(2645), false},
+		"json.Number with an exponent":                 {json.Number("2.5E-2"), bps.NewFromBasisPoint(250), false},
+		"json.Number with a lowercase exponent":        {json.Number("1e-3"), bps.NewFromPPM(big.NewInt(1000)), false},
+		"json.Number below ppb":                        {json.Number("2.5e-9"), bps.NewFromPPB(big.NewInt(2)), false},
+		"json.Number is the same as float64":           {json.Number("0.0000000025"), bps.NewFromPPB(big.NewInt(2)), false},
+		"float64":                                      {0.02645, bps.NewFromDeciBasisPoint(2645), false},
+		"float64 below ppb":                            {0.0000000025, bps.NewFromPPB(big.NewInt(2)), false},
+		"float32":                                      {float32(0.1), bps.NewFromPercentage(10), false},
+		"small float32":                                {float32(0.00001), bps.NewFromPPM(big.NewInt(10)), false},
+		"If invalid string, it should return an error": {"2.645 percent", nil, true},
+		"If NaN, it should return an error":            {math.NaN(), nil, true},
+		"If json.Number is a fraction, it should return an error": {json.Number("1/3"), nil, true},
+		"If json.Number is invalid, it should return an error":    {json.Number("0x10"), nil, true},
+		"If nil, it should return an error":                       {nil, nil, true},
+		"If bool, it should return an error":                      {true, nil, true},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var got bps.BPS
+			err := (*bpsgraphql.BPS)(&got).UnmarshalGQL(tt.v)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BPS.UnmarshalGQL() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(&got, tt.want) {
+				t.Errorf("BPS.UnmarshalGQL() = %v, want %v", got.PPBs(), tt.want.PPBs())
+			}
+		})
+	}
+}
+
+func TestNullBPS(t *testing.T) {
+	tests := map[string]struct {
+		v        interface{}
+		want     bps.NullBPS
+		wantJSON string
+	}{
+		"null": {nil, bps.NullBPS{}, "null"},
+		"value": {
+			"2.645%",
+			bps.NullBPS{BPS: *bps.NewFromDeciBasisPoint(2645), Valid: true},
+			`"0.02645"`,
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got := bpsgraphql.NullBPS{BPS: *bps.NewFromAmount(1), Valid: true}
+			if err := got.UnmarshalGQL(tt.v); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(bps.NullBPS(got), tt.want) {
+				t.Errorf("NullBPS.UnmarshalGQL() = %v, want %v", got, tt.want)
+			}
+			var buf bytes.Buffer
+			got.MarshalGQL(&buf)
+			if buf.String() != tt.wantJSON {
+				t.Errorf("NullBPS.MarshalGQL() = %v, want %v", buf.String(), tt.wantJSON)
+			}
+		})
+	}
+
+	var n bpsgraphql.NullBPS
+	if err := n.UnmarshalGQL(true); err == nil || n.Valid {
+		t.Errorf("NullBPS.UnmarshalGQL() = %v, %v, want an error", n, err)
+	}
+}
+
+func TestSchema(t *testing.T) {
+	t.Parallel()
+	if !strings.Contains(bpsgraphql.Schema, "scalar BPS") {
+		t.Errorf("Schema = %v, want the BPS scalar", bpsgraphql.Schema)
+	}
+}
+
+func ExampleBPS() {
+	var rate bps.BPS
+	_ = (*bpsgraphql.BPS)(&rate).UnmarshalGQL("2.645%")
+	bpsgraphql.BPS(rate).MarshalGQL(os.Stdout)
+	fmt.Println()
+
+	_ = (*bpsgraphql.BPS)(&rate).UnmarshalGQL(0.1)
+	bpsgraphql.BPS(rate).MarshalGQL(os.Stdout)
+	fmt.Println()
+	// Output:
+	// "0.02645"
+	// "0.1"
+}
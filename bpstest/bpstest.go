@@ -0,0 +1,74 @@
+// Copyright © 2020 Merpay, Inc. All rights reserved.
+
+// Package bpstest provides helpers to test code using *bps.BPS.
+//
+// AssertEqual compares values numerically instead of reflect.DeepEqual, which compares the internal representation,
+// and reports them in several units so that a mismatch is readable:
+//
+//	bpstest.AssertEqual(t, fee, bps.NewFromDeciBasisPoint(2645))
+//	// got 0.0264 (2.64%, 264bp, 26400000ppb), want 0.02645 (2.645%, 264.5bp, 26450000ppb), diff -0.00005 (-50000ppb)
+//
+// Value generates random values for testing/quick, and RoundTrip and Golden test a pair of format and parse functions
+// against Vectors or any values.
+package bpstest // import "go.mercari.io/go-bps/bpstest"
+
+import (
+	"strings"
+	"testing"
+
+	"go.mercari.io/go-bps/bps"
+)
+
+// AssertEqual reports an error to t and returns false if got and want are not equal numerically.
+// nil is equal to zero like the operations of bps.BPS.
+func AssertEqual(t testing.TB, got, want *bps.BPS) bool {
+	t.Helper()
+	if got.Equal(want) {
+		return true
+	}
+	t.Errorf("got %s, want %s, diff %s", Describe(got), Describe(want), describeDiff(got, want))
+	return false
+}
+
+// AssertEqualIn reports an error to t and returns false if got and want are not equal when they are rounded down to `u`,
+// e.g. AssertEqualIn(t, got, want, bps.BasisPoint) ignores the fraction less than 1 basis point.
+func AssertEqualIn(t testing.TB, got, want *bps.BPS, u bps.Unit) bool {
+	t.Helper()
+	if got.TruncateTo(u).Equal(want.TruncateTo(u)) {
+		return true
+	}
+	t.Errorf("got %s, want %s in %s, diff %s", Describe(got), Describe(want), unitName(u), describeDiff(got, want))
+	return false
+}
+
+// Describe returns `b` in several units like "0.02645 (2.645%, 264.5bp, 26450000ppb)".
+func Describe(b *bps.BPS) string {
+	if b == nil {
+		return "<nil>"
+	}
+	var sb strings.Builder
+	sb.WriteString(b.CanonicalString())
+	sb.WriteString(" (")
+	for i, u := range []bps.Unit{bps.Percentage, bps.BasisPoint, bps.PPB} {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(b.UnitString(u))
+	}
+	sb.WriteString(")")
+	return sb.String()
+}
+
+// describeDiff returns got - want like "-0.00005 (-50000ppb)".
+func describeDiff(got, want *bps.BPS) string {
+	d := got.Sub(want)
+	return d.CanonicalString() + " (" + d.UnitString(bps.PPB) + ")"
+}
+
+// unitName returns the name of `u` to report.
+func unitName(u bps.Unit) string {
+	if u == bps.Unity {
+		return "amount"
+	}
+	return u.String()
+}
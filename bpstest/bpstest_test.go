@@ -0,0 +1,241 @@
+package bpstest_test
+
+import (
+	"flag"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"go.mercari.io/go-bps/bps"
+	"go.mercari.io/go-bps/bpstest"
+)
+
+// recorder is a testing.TB which records the errors instead of failing the test.
+type recorder struct {
+	testing.TB
+	errors []string
+	fatal  bool
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.Errorf(format, args...)
+	r.fatal = true
+}
+
+var textCodec = bpstest.Codec{
+	Format: func(b *bps.BPS) ([]byte, error) {
+		return b.AppendText(nil)
+	},
+	Parse: func(data []byte) (*bps.BPS, error) {
+		b := new(bps.BPS)
+		return b, b.UnmarshalText(data)
+	},
+}
+
+var percentCodec = bpstest.Codec{
+	Format: func(b *bps.BPS) ([]byte, error) {
+		return []byte(b.UnitString(bps.Percentage)), nil
+	},
+	Parse: func(data []byte) (*bps.BPS, error) {
+		return bps.NewFromUnitString(string(data))
+	},
+}
+
+func TestAssertEqual(t *testing.T) {
+	tests := map[string]struct {
+		got, want *bps.BPS
+		wantOK    bool
+		wantError string
+	}{
+		"equal": {
+			bps.NewFromDeciBasisPoint(2645),
+			bps.NewFromPPB(big.NewInt(26450000)),
+			true,
+			"",
+		},
+		"nil is equal to zero": {
+			nil,
+			bps.NewFromAmount(0),
+			true,
+			"",
+		},
+		"not equal": {
+			bps.NewFromBasisPoint(264),
+			bps.NewFromDeciBasisPoint(2645),
+			false,
+			"got 0.0264 (2.64%, 264bp, 26400000ppb), want 0.02645 (2.645%, 264.5bp, 26450000ppb), diff -0.00005 (-50000ppb)",
+		},
+		"nil is not equal to 1 ppb": {
+			nil,
+			bps.NewFromPPB(big.NewInt(1)),
+			false,
+			"got <nil>, want 0.000000001 (0.0000001%, 0.00001bp, 1ppb), diff -0.000000001 (-1ppb)",
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			r := &recorder{TB: t}
+			if got := bpstest.AssertEqual(r, tt.got, tt.want); got != tt.wantOK {
+				t.Errorf("AssertEqual() = %v, want %v", got, tt.wantOK)
+			}
+			if got := strings.Join(r.errors, "\n"); got != tt.wantError {
+				t.Errorf("AssertEqual() reports %q, want %q", got, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestAssertEqualIn(t *testing.T) {
+	t.Parallel()
+
+	r := &recorder{TB: t}
+	if !bpstest.AssertEqualIn(r, bps.NewFromDeciBasisPoint(2641), bps.NewFromDeciBasisPoint(2649), bps.BasisPoint) {
+		t.Errorf("AssertEqualIn() = false, want true: %v", r.errors)
+	}
+	if bpstest.AssertEqualIn(r, bps.NewFromDeciBasisPoint(2641), bps.NewFromDeciBasisPoint(2651), bps.BasisPoint) {
+		t.Error("AssertEqualIn() = true, want false")
+	}
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "in bp") {
+		t.Errorf("AssertEqualIn() reports %q", r.errors)
+	}
+}
+
+func TestRandom(t *testing.T) {
+	t.Parallel()
+
+	var zero, small, overflow int
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		b := bpstest.Random(r)
+		switch {
+		case b.IsZero():
+			zero++
+		case !b.PPBs().IsInt64():
+			overflow++
+		case b.Abs().LessThan(bps.NewFromPPB(big.NewInt(1000))):
+			small++
+		}
+	}
+	if zero == 0 || small == 0 || overflow == 0 {
+		t.Errorf("Random() generates %d zeros, %d small values and %d values overflowing int64", zero, small, overflow)
+	}
+
+	// the same seed generates the same values
+	r1, r2 := rand.New(rand.NewSource(2)), rand.New(rand.NewSource(2))
+	for i := 0; i < 100; i++ {
+		if v1, v2 := bpstest.Random(r1), bpstest.Random(r2); !v1.Equal(v2) {
+			t.Fatalf("Random() = %v and %v with the same seed", v1.PPBs(), v2.PPBs())
+		}
+	}
+}
+
+func TestValue_Quick(t *testing.T) {
+	t.Parallel()
+
+	f := func(x, y bpstest.Value) bool {
+		return x.Add(y.BPS).Sub(y.BPS).Equal(x.BPS)
+	}
+	if err := quick.Check(f, &quick.Config{Rand: rand.New(rand.NewSource(1))}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	r := &recorder{TB: t}
+	bpstest.RoundTrip(r, textCodec, bpstest.Vectors()...)
+	bpstest.RoundTrip(r, percentCodec, bpstest.Vectors()...)
+	if len(r.errors) != 0 {
+		t.Errorf("RoundTrip() reports %q", r.errors)
+	}
+
+	// FloatString rounds, so it doesn't come back
+	lossy := bpstest.Codec{
+		Format: func(b *bps.BPS) ([]byte, error) { return []byte(b.FloatString(2)), nil },
+		Parse:  textCodec.Parse,
+	}
+	bpstest.RoundTrip(r, lossy, bps.NewFromDeciBasisPoint(2645), bps.NewFromAmount(1))
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], `round trip of 0.02645 (2.645%, 264.5bp, 26450000ppb) via "0.03"`) {
+		t.Errorf("RoundTrip() reports %q", r.errors)
+	}
+}
+
+func TestGolden(t *testing.T) {
+	t.Parallel()
+
+	r := &recorder{TB: t}
+	bpstest.Golden(r, filepath.Join("testdata", "text.golden"), textCodec, bpstest.Vectors()...)
+	bpstest.Golden(r, filepath.Join("testdata", "percent.golden"), percentCodec, bpstest.Vectors()...)
+	if len(r.errors) != 0 {
+		t.Errorf("Golden() reports %q", r.errors)
+	}
+}
+
+func TestGolden_Mismatch(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "text.golden")
+	if err := os.WriteFile(path, []byte("0.02645\t\"0.02645\"\n0.01\t\"0.1\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	r := &recorder{TB: t}
+	bpstest.Golden(r, path, textCodec, bps.NewFromDeciBasisPoint(2645), bps.NewFromPercentage(10))
+	want := []string{
+		path + `:2: got "0.1\t\"0.1\"", want "0.01\t\"0.1\""`,
+		path + `:2: parse "0.1" = 0.1 (10%, 1000bp, 100000000ppb), want 0.01 (1%, 100bp, 10000000ppb)`,
+	}
+	if strings.Join(r.errors, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Golden() reports %q, want %q", r.errors, want)
+	}
+
+	r = &recorder{TB: t}
+	bpstest.Golden(r, filepath.Join(t.TempDir(), "missing.golden"), textCodec, bps.NewFromAmount(1))
+	if !r.fatal {
+		t.Errorf("Golden() with a missing file reports %q", r.errors)
+	}
+}
+
+func TestGolden_Update(t *testing.T) {
+	if err := flag.Set("bpstest.update", "true"); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "new", "text.golden")
+	r := &recorder{TB: t}
+	bpstest.Golden(r, path, textCodec, bps.NewFromDeciBasisPoint(2645))
+	if err := flag.Set("bpstest.update", "false"); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.errors) != 0 {
+		t.Fatalf("Golden() reports %q", r.errors)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "0.02645\t\"0.02645\"\n"; string(got) != want {
+		t.Errorf("golden file = %q, want %q", got, want)
+	}
+}
+
+func ExampleDescribe() {
+	fmt.Println(bpstest.Describe(bps.NewFromDeciBasisPoint(2645)))
+	fmt.Println(bpstest.Describe(bps.NewFromAmount(-3)))
+	// Output:
+	// 0.02645 (2.645%, 264.5bp, 26450000ppb)
+	// -3 (-300%, -30000bp, -3000000000ppb)
+}
@@ -0,0 +1,144 @@
+package bpstest
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"math"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"go.mercari.io/go-bps/bps"
+)
+
+var update = flag.Bool("bpstest.update", false, "update the golden files of bpstest.Golden")
+
+// Codec is a pair of functions to format and parse *bps.BPS, e.g. MarshalText and UnmarshalText of a type.
+type Codec struct {
+	Format func(b *bps.BPS) ([]byte, error)
+	Parse  func(data []byte) (*bps.BPS, error)
+}
+
+// Vectors returns the values which cover the edge cases of formatting and parsing,
+// like 1 ppb, the multiples of units, the int64 boundaries and values overflowing int64.
+// They're new instances, so the caller may modify them.
+func Vectors() []*bps.BPS {
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	return []*bps.BPS{
+		bps.NewFromAmount(0),
+		bps.NewFromPPB(big.NewInt(1)),
+		bps.NewFromPPB(big.NewInt(-1)),
+		bps.NewFromPPM(big.NewInt(1)),
+		bps.NewFromDeciBasisPoint(1),
+		bps.NewFromHalfBasisPoint(1),
+		bps.NewFromBasisPoint(1),
+		bps.NewFromDeciBasisPoint(2645),
+		bps.NewFromDeciBasisPoint(-2645),
+		bps.NewFromPercentage(100),
+		bps.NewFromAmount(14999),
+		bps.NewFromPPB(big.NewInt(396723550000)),
+		bps.NewFromPPB(big.NewInt(math.MaxInt64)),
+		bps.NewFromPPB(big.NewInt(math.MinInt64)),
+		bps.NewFromPPB(new(big.Int).Add(big.NewInt(math.MaxInt64), big.NewInt(1))),
+		bps.NewFromPPB(huge),
+		bps.NewFromPPB(new(big.Int).Neg(huge)),
+	}
+}
+
+// RoundTrip reports an error to t for each value which doesn't come back to the same value by formatting and parsing by c.
+func RoundTrip(t testing.TB, c Codec, values ...*bps.BPS) {
+	t.Helper()
+	for _, v := range values {
+		data, err := c.Format(v)
+		if err != nil {
+			t.Errorf("format %s: %v", Describe(v), err)
+			continue
+		}
+		got, err := c.Parse(data)
+		if err != nil {
+			t.Errorf("parse %q formatted from %s: %v", data, Describe(v), err)
+			continue
+		}
+		if !got.Equal(v) {
+			t.Errorf("round trip of %s via %q = %s", Describe(v), data, Describe(got))
+		}
+	}
+}
+
+// Golden compares the values formatted by c with the golden file at `path`, and parses the representations in the file
+// back to the values, so that a change of either the format or the parser is detected.
+// The golden file has a line per value, which is the canonical string and the quoted representation separated by a tab.
+//
+// Run the test with the -bpstest.update flag to write the golden file, e.g. go test -run TestFormat -args -bpstest.update
+func Golden(t testing.TB, path string, c Codec, values ...*bps.BPS) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	for _, v := range values {
+		data, err := c.Format(v)
+		if err != nil {
+			t.Errorf("format %s: %v", Describe(v), err)
+			return
+		}
+		fmt.Fprintf(&buf, "%s\t%s\n", v.CanonicalString(), strconv.Quote(string(data)))
+	}
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("update golden file: %v", err)
+		}
+		if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+			t.Fatalf("update golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file: %v (run with -bpstest.update to create it)", err)
+	}
+	gotLines, wantLines := strings.SplitAfter(buf.String(), "\n"), strings.SplitAfter(string(want), "\n")
+	for i := 0; i < len(gotLines) || i < len(wantLines); i++ {
+		var got, want string
+		if i < len(gotLines) {
+			got = gotLines[i]
+		}
+		if i < len(wantLines) {
+			want = wantLines[i]
+		}
+		if got != want {
+			t.Errorf("%s:%d: got %q, want %q", path, i+1, strings.TrimSuffix(got, "\n"), strings.TrimSuffix(want, "\n"))
+		}
+	}
+
+	s := bufio.NewScanner(bytes.NewReader(want))
+	for line := 1; s.Scan(); line++ {
+		fields := strings.SplitN(s.Text(), "\t", 2)
+		if len(fields) != 2 {
+			t.Errorf("%s:%d: malformed line %q", path, line, s.Text())
+			continue
+		}
+		wantV, err := bps.NewFromString(fields[0])
+		if err != nil {
+			t.Errorf("%s:%d: malformed value: %v", path, line, err)
+			continue
+		}
+		data, err := strconv.Unquote(fields[1])
+		if err != nil {
+			t.Errorf("%s:%d: malformed representation: %v", path, line, err)
+			continue
+		}
+		got, err := c.Parse([]byte(data))
+		if err != nil {
+			t.Errorf("%s:%d: parse %q: %v", path, line, data, err)
+			continue
+		}
+		if !got.Equal(wantV) {
+			t.Errorf("%s:%d: parse %q = %s, want %s", path, line, data, Describe(got), Describe(wantV))
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package bpstest
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"testing/quick"
+
+	"go.mercari.io/go-bps/bps"
+)
+
+// make sure that Value implements quick.Generator.
+var _ quick.Generator = Value{}
+
+// Value is a random *bps.BPS generated by Random for testing/quick:
+//
+//	f := func(v bpstest.Value) bool {
+//		return v.Neg().Neg().Equal(v.BPS)
+//	}
+//	if err := quick.Check(f, nil); err != nil {
+//		t.Error(err)
+//	}
+type Value struct {
+	*bps.BPS
+}
+
+// Generate implements the quick.Generator interface. size is ignored since Random chooses the magnitude by itself.
+func (Value) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(Value{Random(r)})
+}
+
+// Random returns a random value across magnitudes and signs.
+// Beside uniformly random int64 ppbs, it chooses the values which are likely to reveal bugs more often:
+// zero, a few ppbs, multiples of units, values around the int64 boundaries and values overflowing int64.
+func Random(r *rand.Rand) *bps.BPS {
+	var v *big.Int
+	switch r.Intn(7) {
+	case 0:
+		v = new(big.Int)
+	case 1:
+		// a few ppbs
+		v = big.NewInt(r.Int63n(2000) - 1000)
+	case 2:
+		// on the grid of a unit like 2.5% or 26bp
+		units := []bps.Unit{bps.PPM, bps.DeciBasisPoint, bps.HalfBasisPoint, bps.BasisPoint, bps.Percentage, bps.Unity}
+		u := units[r.Intn(len(units))]
+		return new(bps.BPS).SetInt64In(u, r.Int63n(2000001)-1000000)
+	case 3:
+		// around the int64 boundaries
+		v = big.NewInt(math.MaxInt64 - r.Int63n(1000))
+		if r.Intn(2) == 0 {
+			v.Neg(v).Sub(v, big.NewInt(1))
+		}
+		return bps.NewFromPPB(v.Add(v, big.NewInt(r.Int63n(2000)-1000)))
+	case 4:
+		// beyond int64 up to 128 bits
+		v = new(big.Int).Rand(r, new(big.Int).Lsh(big.NewInt(1), uint(64+r.Intn(64))))
+	default:
+		// any magnitude of int64 in bits
+		v = big.NewInt(r.Int63n(math.MaxInt64) >> uint(r.Intn(63)))
+	}
+	if r.Intn(2) == 0 {
+		v.Neg(v)
+	}
+	return bps.NewFromPPB(v)
+}
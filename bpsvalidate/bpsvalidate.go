@@ -0,0 +1,335 @@
+// Copyright © 2020 Merpay, Inc. All rights reserved.
+
+// Package bpsvalidate validates BPS fields of structs by the `bps` struct tag.
+//
+// A tag is a comma-separated list of rules:
+//
+//	type Request struct {
+//		Rate *bps.BPS `bps:"required,min=0,max=100%,unit=halfbp"`
+//	}
+//
+// The supported rules are:
+//
+//	required  the value must not be nil, or must be valid for NullBPS
+//	min=v     the value must be v or more
+//	max=v     the value must be v or less
+//	gt=v      the value must be greater than v
+//	lt=v      the value must be less than v
+//	unit=u    the value must be a multiple of u, that means its granularity
+//
+// v is parsed by bps.NewFromUnitString, e.g. "0", "1.5%" or "150bp".
+// u is a unit symbol accepted by bps.ParseUnit like "%", "bp" or "hbp", or one of the names
+// "percent", "decibp" and "halfbp".
+// The rules except required are skipped for a nil value.
+//
+// The tag can be put on *bps.BPS, bps.BPS, bps.NullBPS, the types which have the `BPS() *bps.BPS` method
+// like bps.Rate and bps.Amount, and slices or arrays of them, in which case every element is validated.
+// Untagged structs, pointers to structs, slices, arrays and maps are walked recursively, and `bps:"-"` skips a field.
+// Each pointer and map is walked only once, so a cyclic value like a tree with parent pointers can be validated.
+package bpsvalidate // import "go.mercari.io/go-bps/bpsvalidate"
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"go.mercari.io/go-bps/bps"
+)
+
+// FieldError is a violation of a rule by a field.
+type FieldError struct {
+	// Field is the path to the field like "Fees[2].Rate".
+	Field string
+	// Rule is the name of the violated rule like "max".
+	Rule string
+	// Param is the parameter of the rule as written in the tag like "100%".
+	Param string
+	// Value is the value of the field, which is nil if Rule is "required".
+	Value *bps.BPS
+}
+
+func (e *FieldError) Error() string {
+	var msg string
+	switch e.Rule {
+	case "required":
+		msg = "is required"
+	case "min":
+		msg = fmt.Sprintf("must be at least %s, got %s", e.Param, e.Value.UnitString(bps.Percentage))
+	case "max":
+		msg = fmt.Sprintf("must be at most %s, got %s", e.Param, e.Value.UnitString(bps.Percentage))
+	case "gt":
+		msg = fmt.Sprintf("must be greater than %s, got %s", e.Param, e.Value.UnitString(bps.Percentage))
+	case "lt":
+		msg = fmt.Sprintf("must be less than %s, got %s", e.Param, e.Value.UnitString(bps.Percentage))
+	case "unit":
+		msg = fmt.Sprintf("must be a multiple of %s, got %s", e.Param, e.Value.UnitString(bps.Percentage))
+	default:
+		msg = fmt.Sprintf("violates %s", e.Rule)
+	}
+	if e.Field == "" {
+		return msg
+	}
+	return e.Field + ": " + msg
+}
+
+// Errors is the list of all the violations found by Struct.
+type Errors []*FieldError
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Struct validates the tagged fields of v, which is a struct, a pointer to a struct, or a slice, array or map of them.
+// It returns Errors if some fields violate their rules, or another error if a tag is malformed or put on an unsupported type.
+func Struct(v interface{}) error {
+	w := walker{seen: make(map[visit]bool)}
+	if err := w.walk("", reflect.ValueOf(v)); err != nil {
+		return err
+	}
+	if len(w.errs) > 0 {
+		return w.errs
+	}
+	return nil
+}
+
+// Var validates a single value `b` by tag like "min=0,max=100%".
+// It returns a *FieldError without Field if `b` violates a rule.
+func Var(b *bps.BPS, tag string) error {
+	rs, err := parseTag(tag)
+	if err != nil {
+		return err
+	}
+	var errs Errors
+	check("", b, rs, &errs)
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+var (
+	bpsType     = reflect.TypeOf(bps.BPS{})
+	bpsPtrType  = reflect.TypeOf((*bps.BPS)(nil))
+	nullBPSType = reflect.TypeOf(bps.NullBPS{})
+	bpserType   = reflect.TypeOf((*interface{ BPS() *bps.BPS })(nil)).Elem()
+)
+
+// visit is a pointer or a map which the walker has visited.
+// The type is needed since a struct and its first field have the same address.
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// walker walks a value to validate the tagged fields.
+type walker struct {
+	errs Errors
+	// seen is the set of the pointers and maps visited, so that a cyclic value like a linked list doesn't recurse forever.
+	seen map[visit]bool
+}
+
+// walk finds the tagged fields in v recursively.
+func (w *walker) walk(path string, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Kind() != reflect.Interface {
+			k := visit{v.Pointer(), v.Type()}
+			if w.seen[k] {
+				return nil
+			}
+			w.seen[k] = true
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return w.walk(path, v.Elem())
+	case reflect.Struct:
+		if v.Type() == bpsType || v.Type() == nullBPSType {
+			return nil
+		}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" {
+				// unexported
+				continue
+			}
+			tag, ok := f.Tag.Lookup("bps")
+			if tag == "-" {
+				continue
+			}
+			p := path
+			if !f.Anonymous {
+				p = joinPath(path, f.Name)
+			}
+			if !ok {
+				if err := w.walk(p, v.Field(i)); err != nil {
+					return err
+				}
+				continue
+			}
+			rs, err := parseTag(tag)
+			if err != nil {
+				return fmt.Errorf("bpsvalidate: %s: %w", p, err)
+			}
+			if err := checkValue(p, v.Field(i), rs, &w.errs); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := w.walk(fmt.Sprintf("%s[%d]", path, i), v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			if err := w.walk(fmt.Sprintf("%s[%v]", path, iter.Key()), iter.Value()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkValue validates the tagged value v by rs.
+func checkValue(path string, v reflect.Value, rs []rule, errs *Errors) error {
+	switch t := v.Type(); {
+	case t == bpsPtrType:
+		check(path, v.Interface().(*bps.BPS), rs, errs)
+	case t == bpsType:
+		b := v.Interface().(bps.BPS)
+		check(path, &b, rs, errs)
+	case t == nullBPSType:
+		n := v.Interface().(bps.NullBPS)
+		if !n.Valid {
+			check(path, nil, rs, errs)
+			return nil
+		}
+		check(path, &n.BPS, rs, errs)
+	case t.Implements(bpserType):
+		if t.Kind() == reflect.Ptr && v.IsNil() {
+			check(path, nil, rs, errs)
+			return nil
+		}
+		check(path, v.Interface().(interface{ BPS() *bps.BPS }).BPS(), rs, errs)
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := checkValue(fmt.Sprintf("%s[%d]", path, i), v.Index(i), rs, errs); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("bpsvalidate: %s: unsupported type %s for the bps tag", path, t)
+	}
+	return nil
+}
+
+// check validates `b` by rs, which is nil if the value is missing.
+func check(path string, b *bps.BPS, rs []rule, errs *Errors) {
+	for _, r := range rs {
+		if b == nil {
+			if r.name == "required" {
+				*errs = append(*errs, &FieldError{Field: path, Rule: r.name})
+			}
+			continue
+		}
+		var ok bool
+		switch r.name {
+		case "required":
+			ok = true
+		case "min":
+			ok = b.GreaterThanOrEqual(r.bound)
+		case "max":
+			ok = b.LessThanOrEqual(r.bound)
+		case "gt":
+			ok = b.GreaterThan(r.bound)
+		case "lt":
+			ok = b.LessThan(r.bound)
+		case "unit":
+			ok = b.IsMultipleOf(r.unit)
+		}
+		if !ok {
+			*errs = append(*errs, &FieldError{Field: path, Rule: r.name, Param: r.param, Value: b})
+		}
+	}
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// rule is a parsed rule of the bps tag.
+type rule struct {
+	name  string
+	param string
+	bound *bps.BPS
+	unit  bps.Unit
+}
+
+// unitNames are the names accepted by the unit rule in addition to the symbols.
+var unitNames = map[string]bps.Unit{
+	"percent": bps.Percentage,
+	"decibp":  bps.DeciBasisPoint,
+	"halfbp":  bps.HalfBasisPoint,
+}
+
+// tagCache caches the parsed rules by the tag.
+var tagCache sync.Map
+
+// parseTag returns the rules of tag.
+func parseTag(tag string) ([]rule, error) {
+	if rs, ok := tagCache.Load(tag); ok {
+		return rs.([]rule), nil
+	}
+
+	var rs []rule
+	for _, s := range strings.Split(tag, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		r := rule{name: s}
+		if i := strings.IndexByte(s, '='); i >= 0 {
+			r.name, r.param = strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+1:])
+		}
+
+		var err error
+		switch r.name {
+		case "required":
+			if r.param != "" {
+				return nil, fmt.Errorf("invalid rule %q: required takes no parameter", s)
+			}
+		case "min", "max", "gt", "lt":
+			if r.bound, err = bps.NewFromUnitString(r.param); err != nil {
+				return nil, fmt.Errorf("invalid rule %q: %w", s, err)
+			}
+		case "unit":
+			var ok bool
+			if r.unit, ok = unitNames[strings.ToLower(r.param)]; !ok {
+				if r.unit, err = bps.ParseUnit(r.param); err != nil {
+					return nil, fmt.Errorf("invalid rule %q: %w", s, err)
+				}
+			}
+		default:
+			return nil, fmt.Errorf("unknown rule %q", s)
+		}
+		rs = append(rs, r)
+	}
+
+	tagCache.Store(tag, rs)
+	return rs, nil
+}
@@ -0,0 +1,239 @@
+package bpsvalidate_test
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"go.mercari.io/go-bps/bps"
+	"go.mercari.io/go-bps/bpsvalidate"
+)
+
+type fee struct {
+	Rate  *bps.BPS    `bps:"required,min=0,max=100%,unit=halfbp"`
+	Cap   bps.NullBPS `bps:"gt=0"`
+	Floor bps.BPS     `bps:"min=0"`
+}
+
+type order struct {
+	Fee      fee
+	Fees     []*fee
+	Discount bps.Rate   `bps:"lt=50%"`
+	Steps    []*bps.BPS `bps:"required,unit=bp"`
+	Ignored  *bps.BPS   `bps:"-"`
+	Untagged *bps.BPS
+	internal *bps.BPS `bps:"required"`
+}
+
+func TestStruct(t *testing.T) {
+	valid := func() order {
+		return order{
+			Fee: fee{
+				Rate:  bps.NewFromHalfBasisPoint(3),
+				Cap:   bps.NullBPS{BPS: *bps.NewFromPercentage(10), Valid: true},
+				Floor: *bps.NewFromAmount(0),
+			},
+			Fees:     []*fee{{Rate: bps.NewFromPercentage(100)}, nil},
+			Discount: bps.RateFromBPS(bps.NewFromPercentage(20)),
+			Steps:    []*bps.BPS{bps.NewFromBasisPoint(1), bps.NewFromBasisPoint(-2)},
+		}
+	}
+
+	tests := map[string]struct {
+		modify func(o *order)
+		want   []string
+	}{
+		"valid": {
+			func(o *order) {},
+			nil,
+		},
+		"required": {
+			func(o *order) { o.Fee.Rate = nil },
+			[]string{"Fee.Rate: is required"},
+		},
+		"min": {
+			func(o *order) { o.Fee.Rate = bps.NewFromHalfBasisPoint(-1) },
+			[]string{"Fee.Rate: must be at least 0, got -0.005%"},
+		},
+		"max and unit in a slice": {
+			func(o *order) { o.Fees[0].Rate = bps.NewFromDeciBasisPoint(100001) },
+			[]string{
+				"Fees[0].Rate: must be at most 100%, got 100.001%",
+				"Fees[0].Rate: must be a multiple of halfbp, got 100.001%",
+			},
+		},
+		"gt on NullBPS": {
+			func(o *order) { o.Fee.Cap = bps.NullBPS{Valid: true} },
+			[]string{"Fee.Cap: must be greater than 0, got 0%"},
+		},
+		"NULL skips the rules except required": {
+			func(o *order) { o.Fee.Cap = bps.NullBPS{} },
+			nil,
+		},
+		"min on BPS": {
+			func(o *order) { o.Fee.Floor = *bps.NewFromPercentage(-1) },
+			[]string{"Fee.Floor: must be at least 0, got -1%"},
+		},
+		"lt on Rate": {
+			func(o *order) { o.Discount = bps.RateFromBPS(bps.NewFromPercentage(50)) },
+			[]string{"Discount: must be less than 50%, got 50%"},
+		},
+		"each element of a slice": {
+			func(o *order) { o.Steps = append(o.Steps, nil, bps.NewFromDeciBasisPoint(1)) },
+			[]string{
+				"Steps[2]: is required",
+				"Steps[3]: must be a multiple of bp, got 0.001%",
+			},
+		},
+		"ignored, untagged and unexported fields": {
+			func(o *order) {
+				o.Ignored = bps.NewFromPercentage(-1)
+				o.Untagged = bps.NewFromPercentage(-1)
+			},
+			nil,
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			o := valid()
+			tt.modify(&o)
+			err := bpsvalidate.Struct(&o)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("Struct() error = %v, want nil", err)
+				}
+				return
+			}
+			var errs bpsvalidate.Errors
+			if !errors.As(err, &errs) {
+				t.Fatalf("Struct() error = %v, want Errors", err)
+			}
+			got := make([]string, len(errs))
+			for i, e := range errs {
+				got[i] = e.Error()
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Struct() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStruct_Collections(t *testing.T) {
+	t.Parallel()
+
+	v := map[string][]fee{
+		"jp": {{Rate: bps.NewFromPercentage(1)}, {Rate: nil}},
+	}
+	err := bpsvalidate.Struct(v)
+	if err == nil || err.Error() != "[jp][1].Rate: is required" {
+		t.Errorf("Struct() error = %v", err)
+	}
+	if err := bpsvalidate.Struct(nil); err != nil {
+		t.Errorf("Struct(nil) error = %v", err)
+	}
+}
+
+func TestStruct_Cyclic(t *testing.T) {
+	t.Parallel()
+
+	type node struct {
+		Rate   *bps.BPS `bps:"required"`
+		Parent *node
+		Links  map[string]*node
+	}
+	n := &node{Rate: bps.NewFromPercentage(1)}
+	n.Parent = n
+	n.Links = map[string]*node{"self": n, "child": {Parent: n}}
+	err := bpsvalidate.Struct(n)
+	if err == nil || err.Error() != "Links[child].Rate: is required" {
+		t.Errorf("Struct() error = %v", err)
+	}
+}
+
+func TestStruct_InvalidTag(t *testing.T) {
+	tests := map[string]interface{}{
+		"unknown rule": &struct {
+			Rate *bps.BPS `bps:"positive"`
+		}{},
+		"invalid bound": &struct {
+			Rate *bps.BPS `bps:"min=x"`
+		}{},
+		"invalid unit": &struct {
+			Rate *bps.BPS `bps:"unit=bips"`
+		}{},
+		"required with a parameter": &struct {
+			Rate *bps.BPS `bps:"required=true"`
+		}{},
+		"unsupported type": &struct {
+			Rate float64 `bps:"min=0"`
+		}{},
+	}
+	for name, v := range tests {
+		v := v
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := bpsvalidate.Struct(v)
+			var errs bpsvalidate.Errors
+			if err == nil || errors.As(err, &errs) {
+				t.Errorf("Struct() error = %v, want a tag error", err)
+			}
+		})
+	}
+}
+
+func TestVar(t *testing.T) {
+	tests := map[string]struct {
+		b       *bps.BPS
+		tag     string
+		want    string
+		wantErr bool
+	}{
+		"valid":           {bps.NewFromPPB(big.NewInt(15)), "min=0,unit=ppb", "", false},
+		"violation":       {bps.NewFromPercentage(101), "min=0,max=100%", "must be at most 100%, got 101%", false},
+		"nil is optional": {nil, "min=0", "", false},
+		"If invalid tag, it should return an error": {nil, "min", "", true},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := bpsvalidate.Var(tt.b, tt.tag)
+			var fe *bpsvalidate.FieldError
+			if tt.wantErr {
+				if err == nil || errors.As(err, &fe) {
+					t.Errorf("Var() error = %v, want a tag error", err)
+				}
+				return
+			}
+			got := ""
+			if err != nil {
+				got = err.Error()
+			}
+			if got != tt.want {
+				t.Errorf("Var() error = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Example() {
+	type Request struct {
+		Rate *bps.BPS `bps:"required,min=0,max=100%,unit=halfbp"`
+	}
+
+	err := bpsvalidate.Struct(&Request{Rate: bps.NewFromBasisPoint(10050)})
+	fmt.Println(err)
+
+	var errs bpsvalidate.Errors
+	if errors.As(err, &errs) {
+		fmt.Println(errs[0].Field, errs[0].Rule)
+	}
+	// Output:
+	// Rate: must be at most 100%, got 100.5%
+	// Rate max
+}
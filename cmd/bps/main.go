@@ -0,0 +1,414 @@
+// Copyright © 2020 Merpay, Inc. All rights reserved.
+
+// Command bps converts values between units and calculates fees with the exact semantics of the bps package.
+//
+// Usage:
+//
+//	bps convert <value> [--to unit]
+//	bps apply <rate> <amount> [--round mode]
+//	bps sum [--csv] [--column n] [--header] [--unit unit] [file...]
+//	bps avg [--csv] [--column n] [--header] [--unit unit] [file...]
+//
+// A value can have a unit symbol like "2.645%", "264.5bp" or "2645dbp", and one without a symbol is an amount like "0.02645".
+// The thousands separator "," is ignored, e.g. "14,999".
+// sum and avg read one value per line, or a column of CSV with --csv, from the files or stdin.
+// Every command accepts --format json to output JSON instead of text.
+//
+// Examples:
+//
+//	$ bps convert 2.645% --to dbp
+//	2645dbp
+//	$ bps apply 2.645% 14,999 --round half-up
+//	397
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+
+	"go.mercari.io/go-bps/bps"
+)
+
+const usage = `Usage:
+  bps convert <value> [--to unit]
+  bps apply <rate> <amount> [--round mode]
+  bps sum [--csv] [--column n] [--header] [--unit unit] [file...]
+  bps avg [--csv] [--column n] [--header] [--unit unit] [file...]
+
+Units: ppb, ppm, dbp, hbp, bp, %, amount
+Rounding modes: down, up, floor, ceiling, half-up, half-down, half-even, none
+Every command accepts --format text|json.
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// errUsage means the command line is wrong, so run prints the usage.
+var errUsage = errors.New("invalid usage")
+
+// run executes the command and returns the exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	c := &cli{stdin: stdin, stdout: stdout}
+	var err error
+	switch args[0] {
+	case "convert":
+		err = c.convert(args[1:])
+	case "apply":
+		err = c.apply(args[1:])
+	case "sum":
+		err = c.aggregate(args[1:], false)
+	case "avg":
+		err = c.aggregate(args[1:], true)
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	default:
+		err = fmt.Errorf("%w: unknown command %q", errUsage, args[0])
+	}
+
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, flag.ErrHelp):
+		fmt.Fprint(stdout, usage)
+		return 0
+	case errors.Is(err, errUsage):
+		fmt.Fprintf(stderr, "bps: %v\n%s", err, usage)
+		return 2
+	}
+	fmt.Fprintf(stderr, "bps: %v\n", err)
+	return 1
+}
+
+type cli struct {
+	stdin  io.Reader
+	stdout io.Writer
+	format string
+}
+
+// newFlagSet returns a new flag.FlagSet for the command with the common flags.
+func (c *cli) newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&c.format, "format", "text", "output format: text or json")
+	return fs
+}
+
+// parseArgs parses the flags in args, which can be put after the positional arguments unlike flag.FlagSet.Parse,
+// and returns the positional arguments. A negative number like "-1%" is a positional argument.
+func (c *cli) parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var pos []string
+	for len(args) > 0 {
+		switch a := args[0]; {
+		case a == "--":
+			pos = append(pos, args[1:]...)
+			args = nil
+			continue
+		case !strings.HasPrefix(a, "-") || a == "-" || isNegativeNumber(a):
+			pos = append(pos, a)
+			args = args[1:]
+			continue
+		}
+
+		// parse the flags before the next negative number, which flag.FlagSet takes for a flag
+		k := 1
+		for k < len(args) && !isNegativeNumber(args[k]) {
+			k++
+		}
+		if err := fs.Parse(args[:k]); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("%w: %v", errUsage, err)
+		}
+		args = append(append([]string{}, fs.Args()...), args[k:]...)
+	}
+
+	if c.format != "text" && c.format != "json" {
+		return nil, fmt.Errorf("%w: unknown format %q", errUsage, c.format)
+	}
+	return pos, nil
+}
+
+func isNegativeNumber(s string) bool {
+	return len(s) > 1 && s[0] == '-' && (s[1] == '.' || ('0' <= s[1] && s[1] <= '9'))
+}
+
+// output writes v as JSON, or text as a line.
+func (c *cli) output(text string, v interface{}) error {
+	if c.format == "json" {
+		enc := json.NewEncoder(c.stdout)
+		return enc.Encode(v)
+	}
+	_, err := fmt.Fprintln(c.stdout, text)
+	return err
+}
+
+func (c *cli) convert(args []string) error {
+	fs := c.newFlagSet("convert")
+	to := fs.String("to", "%", "unit to convert to")
+	pos, err := c.parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 {
+		return fmt.Errorf("%w: convert takes 1 value", errUsage)
+	}
+	u, err := parseUnit(*to)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	b, err := parseValue(pos[0])
+	if err != nil {
+		return err
+	}
+
+	return c.output(b.UnitString(u), struct {
+		Value string `json:"value"`
+		Unit  string `json:"unit"`
+		PPB   string `json:"ppb"`
+	}{
+		Value: string(b.AppendFloat(nil, u, -1)),
+		Unit:  unitName(u),
+		PPB:   b.PPBs().String(),
+	})
+}
+
+func (c *cli) apply(args []string) error {
+	fs := c.newFlagSet("apply")
+	round := fs.String("round", "half-up", "rounding mode of the result to an integer amount")
+	pos, err := c.parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 2 {
+		return fmt.Errorf("%w: apply takes a rate and an amount", errUsage)
+	}
+	mode, exact, err := parseRoundingMode(*round)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	rate, err := parseValue(pos[0])
+	if err != nil {
+		return err
+	}
+	amount, err := parseValue(pos[1])
+	if err != nil {
+		return err
+	}
+
+	// the product can be finer than ppb, so it's rounded only once from the exact value
+	fee := exactString(new(big.Rat).Mul(rate.Rat(), amount.Rat()))
+	res := fee
+	if !exact {
+		res = bps.RateFromBPS(rate).MulRound(bps.AmountFromBPS(amount), mode).String()
+	}
+	return c.output(res, struct {
+		Rate     string `json:"rate"`
+		Amount   string `json:"amount"`
+		Exact    string `json:"exact"`
+		Result   string `json:"result"`
+		Rounding string `json:"rounding"`
+	}{
+		Rate:     rate.CanonicalString(),
+		Amount:   amount.CanonicalString(),
+		Exact:    fee,
+		Result:   res,
+		Rounding: *round,
+	})
+}
+
+// exactString returns the decimal representation of r without trailing zeros.
+// r must be a product of two BPS values, so it has at most 18 fractional digits.
+func exactString(r *big.Rat) string {
+	s := r.FloatString(18)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// aggregate implements sum, and avg if avg is true.
+func (c *cli) aggregate(args []string, avg bool) error {
+	name := "sum"
+	if avg {
+		name = "avg"
+	}
+	fs := c.newFlagSet(name)
+	var (
+		isCSV  = fs.Bool("csv", false, "read the input as CSV")
+		column = fs.Int("column", 1, "1-based column number of CSV")
+		header = fs.Bool("header", false, "skip the first row of CSV")
+		unit   = fs.String("unit", "amount", "unit of the output")
+	)
+	pos, err := c.parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if *column < 1 {
+		return fmt.Errorf("%w: column must be 1 or more", errUsage)
+	}
+	u, err := parseUnit(*unit)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+
+	var values []*bps.BPS
+	if len(pos) == 0 {
+		pos = []string{"-"}
+	}
+	for _, path := range pos {
+		var vs []*bps.BPS
+		if *isCSV {
+			vs, err = c.readCSV(path, *column-1, *header)
+		} else {
+			vs, err = c.readLines(path)
+		}
+		if err != nil {
+			return err
+		}
+		values = append(values, vs...)
+	}
+	if len(values) == 0 {
+		return errors.New("no values in the input")
+	}
+
+	var res *bps.BPS
+	if avg {
+		res = bps.Avg(values[0], values[1:]...)
+	} else {
+		res = bps.Sum(values[0], values[1:]...)
+	}
+	return c.output(res.UnitString(u), struct {
+		Count  int    `json:"count"`
+		Result string `json:"result"`
+		Unit   string `json:"unit"`
+	}{
+		Count:  len(values),
+		Result: string(res.AppendFloat(nil, u, -1)),
+		Unit:   unitName(u),
+	})
+}
+
+// open returns the reader of path, which is stdin if path is "-".
+func (c *cli) open(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(c.stdin), nil
+	}
+	return os.Open(path)
+}
+
+// readLines reads one value per line from path skipping the empty lines.
+func (c *cli) readLines(path string) ([]*bps.BPS, error) {
+	r, err := c.open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var values []*bps.BPS
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		b, err := parseValue(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+		values = append(values, b)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// readCSV reads the values in the column from a CSV file.
+func (c *cli) readCSV(path string, column int, header bool) ([]*bps.BPS, error) {
+	r, err := c.open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	var values []*bps.BPS
+	for row := 1; ; row++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return values, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if header && row == 1 {
+			continue
+		}
+		if column >= len(record) {
+			return nil, fmt.Errorf("%s:%d: no column %d", path, row, column+1)
+		}
+		b, err := parseValue(record[column])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, row, err)
+		}
+		values = append(values, b)
+	}
+}
+
+// parseValue parses s by bps.NewFromUnitString ignoring the thousands separators.
+func parseValue(s string) (*bps.BPS, error) {
+	return bps.NewFromUnitString(strings.ReplaceAll(strings.TrimSpace(s), ",", ""))
+}
+
+// parseUnit returns the Unit of a symbol accepted by bps.ParseUnit, or "amount" which means bps.Unity.
+func parseUnit(s string) (bps.Unit, error) {
+	if strings.EqualFold(s, "amount") {
+		return bps.Unity, nil
+	}
+	return bps.ParseUnit(s)
+}
+
+// unitName is the inverse of parseUnit.
+func unitName(u bps.Unit) string {
+	if u == bps.Unity {
+		return "amount"
+	}
+	return u.String()
+}
+
+var roundingModes = map[string]bps.RoundingMode{
+	"down":      bps.RoundDown,
+	"up":        bps.RoundUp,
+	"floor":     bps.RoundFloor,
+	"ceiling":   bps.RoundCeiling,
+	"half-up":   bps.RoundHalfUp,
+	"half-down": bps.RoundHalfDown,
+	"half-even": bps.RoundHalfEven,
+}
+
+// parseRoundingMode returns the RoundingMode of s, or true if s is "none" which means no rounding.
+func parseRoundingMode(s string) (bps.RoundingMode, bool, error) {
+	if s == "none" {
+		return 0, true, nil
+	}
+	mode, ok := roundingModes[s]
+	if !ok {
+		return 0, false, fmt.Errorf("unknown rounding mode %q", s)
+	}
+	return mode, false, nil
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "fees.csv")
+	if err := os.WriteFile(csvPath, []byte("id,rate\n1,1.5%\n2,\"2,645dbp\"\n3,0.01\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		args     []string
+		stdin    string
+		want     string
+		wantCode int
+	}{
+		"convert percentage to deci basis point": {
+			[]string{"convert", "2.645%", "--to", "dbp"},
+			"", "2645dbp\n", 0,
+		},
+		"convert with the default unit": {
+			[]string{"convert", "264.5bps"},
+			"", "2.645%\n", 0,
+		},
+		"convert a negative value": {
+			[]string{"convert", "--to=amount", "-1%"},
+			"", "-0.01\n", 0,
+		},
+		"convert to JSON": {
+			[]string{"convert", "2.645%", "--to", "bp", "--format", "json"},
+			"", `{"value":"264.5","unit":"bp","ppb":"26450000"}` + "\n", 0,
+		},
+		"apply a rate rounded half up": {
+			[]string{"apply", "2.645%", "14,999", "--round", "half-up"},
+			"", "397\n", 0,
+		},
+		"apply a rate rounded down": {
+			[]string{"apply", "--round", "down", "2.645%", "14999"},
+			"", "396\n", 0,
+		},
+		"apply a rate without rounding": {
+			[]string{"apply", "2.645%", "14999", "--round", "none"},
+			"", "396.72355\n", 0,
+		},
+		"apply to JSON": {
+			[]string{"apply", "2.645%", "14999", "--format", "json"},
+			"",
+			`{"rate":"0.02645","amount":"14999","exact":"396.72355","result":"397","rounding":"half-up"}` + "\n",
+			0,
+		},
+		"apply to a fractional amount rounded up": {
+			[]string{"apply", "1ppb", "0.5", "--round", "up"},
+			"", "1\n", 0,
+		},
+		"apply to a fractional amount rounded to ceiling": {
+			[]string{"apply", "1ppb", "0.5", "--round", "ceiling"},
+			"", "1\n", 0,
+		},
+		"apply to a negative fractional amount rounded to ceiling": {
+			[]string{"apply", "1ppb", "-0.5", "--round", "ceiling"},
+			"", "0\n", 0,
+		},
+		"apply to a fractional amount rounded half down above the half": {
+			[]string{"apply", "50%", "1.000000001", "--round", "half-down"},
+			"", "1\n", 0,
+		},
+		"apply rounded half down at the half": {
+			[]string{"apply", "50%", "1", "--round", "half-down"},
+			"", "0\n", 0,
+		},
+		"apply to a fractional amount rounded half even above the half": {
+			[]string{"apply", "50%", "1.000000001", "--round", "half-even"},
+			"", "1\n", 0,
+		},
+		"apply rounded half even at the half": {
+			[]string{"apply", "50%", "5", "--round", "half-even"},
+			"", "2\n", 0,
+		},
+		"apply to a fractional amount without rounding": {
+			[]string{"apply", "50%", "1.000000001", "--round", "none"},
+			"", "0.5000000005\n", 0,
+		},
+		"apply to a fractional amount to JSON": {
+			[]string{"apply", "1ppb", "0.5", "--round", "up", "--format", "json"},
+			"",
+			`{"rate":"0.000000001","amount":"0.5","exact":"0.0000000005","result":"1","rounding":"up"}` + "\n",
+			0,
+		},
+		"sum lines from stdin": {
+			[]string{"sum", "--unit", "%"},
+			"1.5%\n\n150bp\n-0.01\n", "2%\n", 0,
+		},
+		"avg lines from stdin": {
+			[]string{"avg"},
+			"1\n2\n", "1.5\n", 0,
+		},
+		"sum a CSV column": {
+			[]string{"sum", csvPath, "--csv", "--column", "2", "--header", "--unit", "dbp"},
+			"", "5145dbp\n", 0,
+		},
+		"avg a CSV column to JSON": {
+			[]string{"avg", "--csv", "--column=2", "--header", "--format=json", csvPath},
+			"", `{"count":3,"result":"0.01715","unit":"amount"}` + "\n", 0,
+		},
+		"help": {
+			[]string{"help"},
+			"", usage, 0,
+		},
+		"If no command, it should exit with 2": {
+			nil, "", "", 2,
+		},
+		"If unknown command, it should exit with 2": {
+			[]string{"div"}, "", "", 2,
+		},
+		"If unknown flag, it should exit with 2": {
+			[]string{"convert", "1%", "--unit", "bp"}, "", "", 2,
+		},
+		"If unknown unit, it should exit with 2": {
+			[]string{"convert", "1%", "--to", "bips"}, "", "", 2,
+		},
+		"If unknown rounding mode, it should exit with 2": {
+			[]string{"apply", "1%", "100", "--round", "banker"}, "", "", 2,
+		},
+		"If unknown format, it should exit with 2": {
+			[]string{"convert", "1%", "--format", "yaml"}, "", "", 2,
+		},
+		"If too many values, it should exit with 2": {
+			[]string{"convert", "1%", "2%"}, "", "", 2,
+		},
+		"If invalid value, it should exit with 1": {
+			[]string{"apply", "1%", "x"}, "", "", 1,
+		},
+		"If invalid line, it should exit with 1": {
+			[]string{"sum"}, "1\nx\n", "", 1,
+		},
+		"If no values, it should exit with 1": {
+			[]string{"avg"}, "\n", "", 1,
+		},
+		"If no such column, it should exit with 1": {
+			[]string{"sum", "--csv", "--column", "3", csvPath}, "", "", 1,
+		},
+		"If no such file, it should exit with 1": {
+			[]string{"sum", filepath.Join(dir, "missing")}, "", "", 1,
+		},
+	}
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var stdout, stderr bytes.Buffer
+			code := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			if code != tt.wantCode {
+				t.Errorf("run() = %v, want %v, stderr: %s", code, tt.wantCode, stderr.String())
+			}
+			if got := stdout.String(); got != tt.want {
+				t.Errorf("run() output = %q, want %q", got, tt.want)
+			}
+			if code != 0 && stderr.Len() == 0 {
+				t.Error("run() should report the error to stderr")
+			}
+		})
+	}
+}